@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aiseeq/claude-hooks/internal/baseline"
+	"github.com/aiseeq/claude-hooks/internal/core"
+	"github.com/aiseeq/claude-hooks/internal/processor"
+	"github.com/aiseeq/claude-hooks/internal/scanner"
+)
+
+// newBaselineCmd создает команды управления baseline известных нарушений
+func newBaselineCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "baseline",
+		Short: "Manage the baseline of known violations",
+		Long: `A baseline records fingerprints (validator, rule, path and a hash of the normalized
+line) of violations that already exist in a repository. Hooks ignore violations that
+match the baseline and report only new ones. Commit the baseline file with the project.`,
+	}
+
+	var output string
+
+	createCmd := &cobra.Command{
+		Use:   "create [dir]",
+		Short: "Record current violations into the baseline file",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBaselineCreate(cmd.Context(), cmd.OutOrStdout(), baselineDir(args), output)
+		},
+	}
+
+	pruneCmd := &cobra.Command{
+		Use:   "prune [dir]",
+		Short: "Drop baseline entries whose violations are fixed",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBaselinePrune(cmd.Context(), cmd.OutOrStdout(), baselineDir(args), output)
+		},
+	}
+
+	for _, sub := range []*cobra.Command{createCmd, pruneCmd} {
+		sub.Flags().StringVarP(&output, "output", "o", "", "Baseline file (default: general.baseline relative to dir)")
+		cmd.AddCommand(sub)
+	}
+
+	return cmd
+}
+
+// runBaselineCreate сканирует директорию и записывает все текущие нарушения
+func runBaselineCreate(ctx context.Context, w io.Writer, dir, output string) error {
+	engine, config, err := newScanEngine()
+	if err != nil {
+		return err
+	}
+
+	path := baselineFile(config, dir, output)
+	current, files, err := collectBaseline(ctx, engine, dir, path)
+	if err != nil {
+		return err
+	}
+	if err := current.Save(path); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "✅ Recorded %d violation(s) from %d file(s) in %s\n", current.Total(), files, path)
+	return nil
+}
+
+// runBaselinePrune удаляет из baseline исправленные нарушения
+func runBaselinePrune(ctx context.Context, w io.Writer, dir, output string) error {
+	engine, config, err := newScanEngine()
+	if err != nil {
+		return err
+	}
+
+	path := baselineFile(config, dir, output)
+	known, err := baseline.Load(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("baseline %s not found, run 'claude-hooks baseline create' first", path)
+		}
+		return err
+	}
+
+	current, _, err := collectBaseline(ctx, engine, dir, path)
+	if err != nil {
+		return err
+	}
+
+	removed := known.Prune(current)
+	if removed == 0 {
+		fmt.Fprintf(w, "✅ %s is up to date (%d violation(s))\n", path, known.Total())
+		return nil
+	}
+	if err := known.Save(path); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "✅ Pruned %d fixed violation(s), %d remain in %s\n", removed, known.Total(), path)
+	return nil
+}
+
+// newScanEngine создает процессор для проверки файлов вне хука
+func newScanEngine() (*processor.Engine, *core.Config, error) {
+	config, err := core.LoadConfig(configPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	logger, err := core.NewLogger(&config.Logger)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create logger: %w", err)
+	}
+	if err := configureRedaction(config, logger); err != nil {
+		return nil, nil, err
+	}
+
+	engine, err := processor.New(config, logger)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create processor: %w", err)
+	}
+
+	return engine, config, nil
+}
+
+// collectBaseline собирает текущие нарушения в директории
+// Пути записываются относительно директории файла baseline
+func collectBaseline(ctx context.Context, engine *processor.Engine, dir, baselinePath string) (*baseline.Baseline, int, error) {
+	current := baseline.New()
+	root := filepath.Dir(baselinePath)
+	files := 0
+
+	err := scanner.Walk([]string{dir}, func(path string) error {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		if absPath == baselinePath {
+			return nil
+		}
+
+		file, err := scanner.LoadFile(absPath)
+		if err != nil || file == nil {
+			return err
+		}
+		file.SetProjectRoot(dir)
+		files++
+
+		violations, err := engine.ValidateFile(ctx, file)
+		if err != nil {
+			return fmt.Errorf("failed to validate %s: %w", path, err)
+		}
+
+		relPath, err := filepath.Rel(root, absPath)
+		if err != nil {
+			return err
+		}
+		lines := strings.Split(file.Content, "\n")
+		for _, violation := range violations {
+			line := ""
+			if violation.Line >= 1 && violation.Line <= len(lines) {
+				line = lines[violation.Line-1]
+			}
+			current.Add(violation.Validator, violation.Type, relPath, line)
+		}
+		return nil
+	})
+
+	return current, files, err
+}
+
+// baselineFile возвращает абсолютный путь к файлу baseline
+func baselineFile(config *core.Config, dir, output string) string {
+	path := output
+	if path == "" {
+		path = baseline.Resolve(config.General.Baseline, dir)
+	}
+	if absPath, err := filepath.Abs(path); err == nil {
+		return absPath
+	}
+	return path
+}
+
+// baselineDir возвращает директорию из аргументов, по умолчанию текущую
+func baselineDir(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	return "."
+}
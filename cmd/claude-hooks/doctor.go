@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aiseeq/claude-hooks/internal/doctor"
+	"github.com/aiseeq/claude-hooks/internal/installer"
+)
+
+// newDoctorCmd создает команду диагностики окружения и регистрации хуков
+func newDoctorCmd() *cobra.Command {
+	var skipPayloads bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check environment and hook wiring",
+		Long: `Diagnoses why hooks may silently not fire: checks that settings.json registers every
+needed hook event with an existing binary and sane timeouts, that the config loads, that
+the log file is writable and that formatter and notifier binaries are in PATH. Finally a
+synthetic payload is sent through each registered hook command.
+Exits with a non-zero code when any check fails.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			code, err := runDoctor(cmd.Context(), cmd.OutOrStdout(), !skipPayloads)
+			exitCode = code
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVar(&skipPayloads, "skip-payloads", false, "Do not run synthetic payloads through the hooks")
+
+	return cmd
+}
+
+// runDoctor выполняет проверки и печатает отчет
+func runDoctor(ctx context.Context, w io.Writer, runHooks bool) (int, error) {
+	projectDir, err := os.Getwd()
+	if err != nil {
+		return 1, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	var sources []doctor.SettingsSource
+	for _, scope := range []string{installer.ScopeUser, installer.ScopeProject, installer.ScopeLocal} {
+		path, err := installer.SettingsPath(scope, projectDir)
+		if err != nil {
+			return 1, err
+		}
+		sources = append(sources, doctor.SettingsSource{Scope: scope, Path: path})
+	}
+
+	executable, err := currentExecutable()
+	if err != nil {
+		return 1, err
+	}
+
+	report := doctor.Run(ctx, doctor.Options{
+		ConfigPath: configPath,
+		Settings:   sources,
+		Executable: executable,
+		RunHooks:   runHooks,
+	})
+
+	counts := make(map[doctor.Status]int)
+	for _, check := range report.Checks {
+		counts[check.Status]++
+		fmt.Fprintf(w, "%s %-40s %s\n", statusIcon(check.Status), check.Name, check.Detail)
+		if check.Hint != "" && check.Status != doctor.StatusPass {
+			fmt.Fprintf(w, "   ↳ %s\n", check.Hint)
+		}
+	}
+	fmt.Fprintf(w, "\n%d passed, %d warnings, %d failed\n", counts[doctor.StatusPass], counts[doctor.StatusWarn], counts[doctor.StatusFail])
+
+	if report.Failed() {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// statusIcon возвращает значок статуса проверки
+func statusIcon(status doctor.Status) string {
+	switch status {
+	case doctor.StatusPass:
+		return "✅"
+	case doctor.StatusWarn:
+		return "⚠️ "
+	default:
+		return "❌"
+	}
+}
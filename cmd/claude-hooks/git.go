@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aiseeq/claude-hooks/internal/gitdiff"
+	"github.com/aiseeq/claude-hooks/internal/installer"
+	"github.com/aiseeq/claude-hooks/internal/report"
+	"github.com/aiseeq/claude-hooks/internal/scanner"
+)
+
+// newGitCmd создает команды интеграции с git
+func newGitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "git",
+		Short: "Git integration",
+		Long:  "Checks commits made by agents via Bash and by humans with the same validators as the hooks.",
+	}
+
+	preCommitCmd := &cobra.Command{
+		Use:   "pre-commit",
+		Short: "Check staged changes (run from .git/hooks/pre-commit)",
+		Long: `Validates the staged version of every changed file and reports violations on added
+lines only. Path exceptions, inline suppressions and the baseline apply.
+Exits with code 1 and blocks the commit when critical violations are found.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := report.ParseFormat(reportFormat)
+			if err != nil {
+				exitCode = 1
+				return err
+			}
+			code, err := runPreCommit(cmd.Context(), cmd.OutOrStdout(), format)
+			exitCode = code
+			return err
+		},
+	}
+	addFormatFlag(preCommitCmd)
+
+	var (
+		binaryPath string
+		force      bool
+	)
+	installHookCmd := &cobra.Command{
+		Use:   "install-hook",
+		Short: "Install the pre-commit hook into the current repository",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInstallGitHook(cmd.Context(), cmd.OutOrStdout(), binaryPath, force)
+		},
+	}
+	installHookCmd.Flags().StringVar(&binaryPath, "binary", "", "Path to claude-hooks binary used in the hook (default: this executable)")
+	installHookCmd.Flags().BoolVar(&force, "force", false, "Replace an existing pre-commit hook (a backup is kept)")
+
+	uninstallHookCmd := &cobra.Command{
+		Use:   "uninstall-hook",
+		Short: "Remove the pre-commit hook installed by claude-hooks",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUninstallGitHook(cmd.Context(), cmd.OutOrStdout())
+		},
+	}
+
+	cmd.AddCommand(preCommitCmd, installHookCmd, uninstallHookCmd)
+	return cmd
+}
+
+// runPreCommit проверяет проиндексированные изменения
+func runPreCommit(ctx context.Context, w io.Writer, format report.Format) (int, error) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return 1, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	root, err := gitdiff.RepoRoot(ctx, workDir)
+	if err != nil {
+		return 1, err
+	}
+
+	diffs, err := gitdiff.Staged(ctx, root)
+	if err != nil {
+		return 1, err
+	}
+	if len(diffs) == 0 {
+		return 0, nil
+	}
+
+	engine, _, err := newScanEngine()
+	if err != nil {
+		return 1, err
+	}
+
+	commitReport := &report.Report{Version: Version}
+	for _, diff := range diffs {
+		if diff.Lines() == 0 {
+			continue
+		}
+
+		content, err := gitdiff.StagedContent(ctx, root, diff.Path)
+		if err != nil {
+			return 1, err
+		}
+		if len(content) > scanner.MaxFileSize {
+			continue
+		}
+		// Путь абсолютный, чтобы исключения и baseline работали так же, как в хуке
+		file := scanner.Analyze(filepath.Join(root, diff.Path), content)
+		if file == nil {
+			continue
+		}
+		file.SetProjectRoot(root)
+
+		violations, err := engine.ValidateFile(ctx, file)
+		if err != nil {
+			return 1, fmt.Errorf("failed to validate %s: %w", diff.Path, err)
+		}
+		violations = engine.FilterBaseline(file, root, violations)
+
+		commitReport.Files = append(commitReport.Files, diff.Path)
+		for _, violation := range violations {
+			if violation.Line > 0 && !diff.Added[violation.Line] {
+				continue
+			}
+			commitReport.Findings = append(commitReport.Findings, report.Finding{Path: diff.Path, Violation: violation})
+		}
+	}
+
+	if len(commitReport.Findings) == 0 {
+		return 0, nil
+	}
+
+	if err := report.Write(w, format, commitReport); err != nil {
+		return 1, fmt.Errorf("failed to write report: %w", err)
+	}
+	if commitReport.Summary().Critical == 0 {
+		return 0, nil
+	}
+
+	if format == report.FormatText {
+		fmt.Fprintln(w, "❌ Commit blocked by claude-hooks: fix the violations above, add a justified suppression or update the baseline (git commit --no-verify skips the check)")
+	}
+	return 1, nil
+}
+
+// runInstallGitHook устанавливает pre-commit hook в текущий репозиторий
+func runInstallGitHook(ctx context.Context, w io.Writer, binaryPath string, force bool) error {
+	hooksDir, err := currentHooksDir(ctx)
+	if err != nil {
+		return err
+	}
+
+	if binaryPath == "" {
+		if binaryPath, err = currentExecutable(); err != nil {
+			return err
+		}
+	}
+	if binaryPath, err = filepath.Abs(binaryPath); err != nil {
+		return fmt.Errorf("failed to resolve binary path: %w", err)
+	}
+
+	hookConfigPath := ""
+	if configPath != "" {
+		if hookConfigPath, err = filepath.Abs(configPath); err != nil {
+			return fmt.Errorf("failed to resolve config path: %w", err)
+		}
+	}
+
+	result, err := installer.InstallGitHook(hooksDir, binaryPath, hookConfigPath, force)
+	if err != nil {
+		return err
+	}
+
+	if !result.Changed {
+		fmt.Fprintf(w, "✅ %s is up to date\n", result.Path)
+		return nil
+	}
+	fmt.Fprintf(w, "✅ Installed %s\n", result.Path)
+	if result.BackupPath != "" {
+		fmt.Fprintf(w, "   Previous hook saved as %s\n", result.BackupPath)
+	}
+	return nil
+}
+
+// runUninstallGitHook удаляет pre-commit hook claude-hooks
+func runUninstallGitHook(ctx context.Context, w io.Writer) error {
+	hooksDir, err := currentHooksDir(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := installer.UninstallGitHook(hooksDir)
+	if err != nil {
+		return err
+	}
+
+	if !result.Changed {
+		fmt.Fprintf(w, "✅ No claude-hooks pre-commit hook in %s\n", hooksDir)
+		return nil
+	}
+	fmt.Fprintf(w, "✅ Removed %s\n", result.Path)
+	return nil
+}
+
+// currentHooksDir возвращает директорию git hooks текущего репозитория
+func currentHooksDir(ctx context.Context) (string, error) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return gitdiff.HooksDir(ctx, workDir)
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+	"github.com/aiseeq/claude-hooks/internal/installer"
+)
+
+// newInstallCmd создает команду регистрации хуков в settings.json Claude Code
+func newInstallCmd() *cobra.Command {
+	var (
+		scope      string
+		binaryPath string
+		dryRun     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Register hooks in Claude Code settings.json",
+		Long: `Merges claude-hooks entries into Claude Code settings.json.
+Matchers are generated from the enabled validators and tools. Running install again
+replaces previously added claude-hooks entries; unrelated hooks are left untouched and
+the original file is backed up next to it.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInstall(cmd.OutOrStdout(), scope, binaryPath, dryRun)
+		},
+	}
+
+	cmd.Flags().StringVar(&scope, "scope", installer.ScopeUser, "Settings scope: user, project or local")
+	cmd.Flags().StringVar(&binaryPath, "binary", "", "Path to claude-hooks binary used in hook commands (default: this executable)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print resulting settings.json without writing it")
+
+	return cmd
+}
+
+// newUninstallCmd создает команду удаления хуков из settings.json
+func newUninstallCmd() *cobra.Command {
+	var (
+		scope  string
+		dryRun bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove claude-hooks entries from Claude Code settings.json",
+		Long:  "Removes only the hook commands that invoke claude-hooks; other hooks and settings are kept.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUninstall(cmd.OutOrStdout(), scope, dryRun)
+		},
+	}
+
+	cmd.Flags().StringVar(&scope, "scope", installer.ScopeUser, "Settings scope: user, project or local")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print resulting settings.json without writing it")
+
+	return cmd
+}
+
+// runInstall регистрирует хуки для включенных валидаторов и инструментов
+func runInstall(w io.Writer, scope, binaryPath string, dryRun bool) error {
+	config, err := core.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if binaryPath == "" {
+		binaryPath, err = currentExecutable()
+		if err != nil {
+			return err
+		}
+	}
+	binaryPath, err = filepath.Abs(binaryPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve binary path: %w", err)
+	}
+
+	// Явно указанный конфиг прописываем в команду, иначе хук возьмет путь по умолчанию
+	hookConfigPath := ""
+	if configPath != "" {
+		if hookConfigPath, err = filepath.Abs(configPath); err != nil {
+			return fmt.Errorf("failed to resolve config path: %w", err)
+		}
+	}
+
+	settingsPath, err := scopeSettingsPath(scope)
+	if err != nil {
+		return err
+	}
+
+	entries := installer.PlanEntries(config, binaryPath, hookConfigPath)
+	result, err := installer.Install(entries, installer.Options{SettingsPath: settingsPath, DryRun: dryRun})
+	if err != nil {
+		return fmt.Errorf("failed to install hooks: %w", err)
+	}
+
+	return reportSettingsChange(w, result, dryRun)
+}
+
+// runUninstall удаляет записи claude-hooks из settings.json
+func runUninstall(w io.Writer, scope string, dryRun bool) error {
+	settingsPath, err := scopeSettingsPath(scope)
+	if err != nil {
+		return err
+	}
+
+	result, err := installer.Uninstall(installer.Options{SettingsPath: settingsPath, DryRun: dryRun})
+	if err != nil {
+		return fmt.Errorf("failed to uninstall hooks: %w", err)
+	}
+
+	return reportSettingsChange(w, result, dryRun)
+}
+
+// reportSettingsChange печатает что было изменено в settings.json
+func reportSettingsChange(w io.Writer, result *installer.Result, dryRun bool) error {
+	if dryRun {
+		_, err := w.Write(result.Content)
+		return err
+	}
+
+	// Записи claude-hooks, написанные вручную, не принадлежат install
+	for _, entry := range result.Kept {
+		fmt.Fprintf(w, "⚠️  %s %s: %s (not added by install, left in place)\n", entry.Event, displayMatcher(entry.Matcher), entry.Command)
+	}
+
+	if !result.Changed {
+		fmt.Fprintf(w, "✅ %s is up to date\n", result.SettingsPath)
+		return nil
+	}
+
+	for _, entry := range result.Removed {
+		fmt.Fprintf(w, "➖ %s %s: %s\n", entry.Event, displayMatcher(entry.Matcher), entry.Command)
+	}
+	for _, entry := range result.Added {
+		fmt.Fprintf(w, "➕ %s %s: %s (timeout %ds)\n", entry.Event, displayMatcher(entry.Matcher), entry.Command, entry.Timeout)
+	}
+	fmt.Fprintf(w, "✅ Updated %s\n", result.SettingsPath)
+	if result.BackupPath != "" {
+		fmt.Fprintf(w, "   Backup: %s\n", result.BackupPath)
+	}
+
+	claudeHooksLogger.Debug("settings updated", "settings", result.SettingsPath, "added", len(result.Added), "removed", len(result.Removed), "operation", "install", "component", "claude_hooks")
+	return nil
+}
+
+// scopeSettingsPath возвращает путь к settings.json для области
+func scopeSettingsPath(scope string) (string, error) {
+	projectDir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	return installer.SettingsPath(scope, projectDir)
+}
+
+// currentExecutable возвращает абсолютный путь к запущенному бинарнику
+func currentExecutable() (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve executable path: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+	return executable, nil
+}
+
+// displayMatcher форматирует matcher для вывода
+func displayMatcher(matcher string) string {
+	if matcher == "" {
+		return "[*]"
+	}
+	return "[" + matcher + "]"
+}
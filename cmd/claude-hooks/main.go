@@ -6,12 +6,18 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/aiseeq/claude-hooks/internal/audit"
 	"github.com/aiseeq/claude-hooks/internal/core"
+	"github.com/aiseeq/claude-hooks/internal/doctor"
 	"github.com/aiseeq/claude-hooks/internal/processor"
+	"github.com/aiseeq/claude-hooks/internal/report"
+	"github.com/aiseeq/claude-hooks/internal/secrets"
+	"github.com/aiseeq/claude-hooks/internal/validators"
 )
 
 // Logger для claude hooks
@@ -23,6 +29,9 @@ var (
 	timeout    time.Duration
 	exitCode   int
 
+	// reportFormat формат вывода нарушений для хуков и сканирования
+	reportFormat string
+
 	// Версионная информация (встраивается через ldflags при сборке)
 	Version     = "dev"
 	BuildNumber = "0"
@@ -31,6 +40,9 @@ var (
 )
 
 func main() {
+	// Секреты маскируются во всех логах и ответах; после загрузки конфигурации - ее правилами
+	core.SetRedactor(secrets.NewRedactor(secrets.DefaultRedactorOptions()))
+
 	// Инициализируем logger
 	var err error
 	claudeHooksLogger, err = core.NewLogger(core.DefaultLoggerConfig())
@@ -61,6 +73,15 @@ Replaces multiple bash scripts with a single, efficient, and maintainable soluti
 		newStopCmd(),
 		newTestCmd(),
 		newConfigCmd(),
+		newInstallCmd(),
+		newUninstallCmd(),
+		newDoctorCmd(),
+		newBaselineCmd(),
+		newScanCmd(),
+		newReplayCmd(),
+		newStatsCmd(),
+		newSecretsCmd(),
+		newGitCmd(),
 		newVersionCmd(),
 	)
 
@@ -75,7 +96,7 @@ Replaces multiple bash scripts with a single, efficient, and maintainable soluti
 
 // newPreToolUseCmd создает команду для PreToolUse hook
 func newPreToolUseCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "pre-tool-use",
 		Short: "Process PreToolUse hook",
 		Long:  "Processes PreToolUse hook for Write, Edit, MultiEdit operations",
@@ -85,11 +106,13 @@ func newPreToolUseCmd() *cobra.Command {
 			return err
 		},
 	}
+	addFormatFlag(cmd)
+	return cmd
 }
 
 // newPostToolUseCmd создает команду для PostToolUse hook
 func newPostToolUseCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "post-tool-use",
 		Short: "Process PostToolUse hook",
 		Long:  "Processes PostToolUse hook for auto-formatting and cleanup",
@@ -99,11 +122,13 @@ func newPostToolUseCmd() *cobra.Command {
 			return err
 		},
 	}
+	addFormatFlag(cmd)
+	return cmd
 }
 
 // newStopCmd создает команду для Stop hook
 func newStopCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "stop",
 		Short: "Process Stop hook",
 		Long:  "Processes Stop hook for notifications and cleanup",
@@ -113,6 +138,13 @@ func newStopCmd() *cobra.Command {
 			return err
 		},
 	}
+	addFormatFlag(cmd)
+	return cmd
+}
+
+// addFormatFlag добавляет флаг формата отчета о нарушениях
+func addFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&reportFormat, "format", string(report.FormatText), "Report format: "+report.FormatList())
 }
 
 // newTestCmd создает команду для тестирования
@@ -180,6 +212,14 @@ func newConfigCmd() *cobra.Command {
 				return initConfig(cmd.Context())
 			},
 		},
+		newConfigMigrateCmd(),
+		&cobra.Command{
+			Use:   "schema",
+			Short: "Print JSON Schema of the configuration file",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return printConfigSchema(cmd.OutOrStdout())
+			},
+		},
 	)
 
 	return cmd
@@ -204,6 +244,11 @@ Each build automatically increments the build number through the Makefile system
 
 // runHook выполняет основную логику хука
 func runHook(ctx context.Context, hookType string) (int, error) {
+	format, err := report.ParseFormat(reportFormat)
+	if err != nil {
+		return 1, err
+	}
+
 	// Создаем контекст с таймаутом
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
@@ -219,6 +264,13 @@ func runHook(ctx context.Context, hookType string) (int, error) {
 	if err != nil {
 		return 1, fmt.Errorf("failed to create logger: %w", err)
 	}
+	if err := configureRedaction(config, logger); err != nil {
+		return 1, err
+	}
+
+	for _, deprecation := range config.Deprecations() {
+		logger.Warn("deprecated config format, run 'claude-hooks config migrate'", "change", deprecation)
+	}
 
 	// Создаем процессор
 	proc, err := processor.New(config, logger)
@@ -234,6 +286,8 @@ func runHook(ctx context.Context, hookType string) (int, error) {
 
 	// Обрабатываем в зависимости от типа хука
 	var response *core.HookResponse
+	var filePath string
+	var auditInput *core.ToolInput
 	switch hookType {
 	case "stop":
 		// Для stop hook парсим входные данные для получения transcript_path
@@ -248,6 +302,12 @@ func runHook(ctx context.Context, hookType string) (int, error) {
 		// Гарантируем правильный ToolName независимо от успеха парсинга
 		toolInput.ToolName = "Stop"
 
+		// Проверка doctor подтверждает только подключение хука: без звука и уведомлений
+		if toolInput.SessionID == doctor.SyntheticSessionID {
+			response = &core.HookResponse{Action: core.HookActionAllow, Message: "Stop probe completed", Level: core.LevelInfo, Timestamp: time.Now()}
+			break
+		}
+
 		// ProcessStop doesn't need toolInput parameter
 		response, err = proc.ProcessStop(ctx)
 	case "pre-tool-use", "post-tool-use":
@@ -256,12 +316,14 @@ func runHook(ctx context.Context, hookType string) (int, error) {
 		if parseErr != nil {
 			return 1, fmt.Errorf("failed to parse input: %w", parseErr)
 		}
+		filePath = toolInput.FilePath
 
 		if hookType == "pre-tool-use" {
 			if verbose {
 				fmt.Printf("🚨 CALLING ProcessPreToolUse with tool=%s, file=%s\n", toolInput.ToolName, toolInput.FilePath)
 			}
 			response, err = proc.ProcessPreToolUse(ctx, toolInput)
+			auditInput = toolInput
 			if verbose {
 				fmt.Printf("🚨 ProcessPreToolUse RETURNED: err=%v\n", err)
 			}
@@ -277,31 +339,45 @@ func runHook(ctx context.Context, hookType string) (int, error) {
 		return 1, err
 	}
 
+	// Найденные секреты не должны попасть в stderr, stdout и журналы
+	core.RedactResponse(response)
+
+	// Журнал аудита не должен ломать хук
+	if auditInput != nil && config.General.AuditFile != "" && auditInput.SessionID != doctor.SyntheticSessionID {
+		entry := audit.NewEntry(auditInput, response)
+		if err := audit.Append(config.General.AuditFile, entry); err != nil {
+			logger.Warn("failed to write audit entry", "error", err)
+		} else if response.Action == core.HookActionBlock && hasFingerprints(response.Violations) {
+			logger.Info("secret blocked, known-safe values can be allowlisted with 'claude-hooks secrets allow --from-audit <id>'", "audit_id", entry.ID)
+		}
+	}
+
 	// Выводим результат
-	if err := outputResponse(response, verbose); err != nil {
+	if format != report.FormatText {
+		err = outputReport(response, filePath, format)
+	} else {
+		err = outputResponse(response, verbose)
+	}
+	if err != nil {
 		return 1, fmt.Errorf("failed to output response: %w", err)
 	}
 
 	// Возвращаем соответствующий exit code
-	switch response.Action {
-	case core.HookActionBlock:
-		return 2, nil // Блокируем операцию
-	case core.HookActionWarn:
-		return 2, nil // Blocking warning для видимости в интерфейсе Claude Code
-	case core.HookActionAllow:
-		return 0, nil // Разрешаем
-	}
-
-	return 0, nil
+	return response.Action.ExitCode(), nil
 }
 
-// outputResponse выводит ответ хука
-func outputResponse(response *core.HookResponse, verbose bool) error {
-	// Минимальное логирование согласно CLAUDE.md принципам
-	if verbose {
-		claudeHooksLogger.Debug("Hook response", "action", string(response.Action), "operation", "output_response")
+// hasFingerprints проверяет, есть ли среди нарушений найденные секреты с отпечатками
+func hasFingerprints(violations []core.Violation) bool {
+	for _, violation := range violations {
+		if violation.Fingerprint != "" {
+			return true
+		}
 	}
+	return false
+}
 
+// printModifiedToolInput выводит модифицированные параметры инструмента в stdout
+func printModifiedToolInput(response *core.HookResponse) {
 	// КРИТИЧЕСКОЕ: если есть модифицированный tool input, выводим его в stdout в JSON формате
 	// Claude Code использует stdout для получения модифицированных параметров
 	if response.ModifiedToolInput != nil {
@@ -315,6 +391,55 @@ func outputResponse(response *core.HookResponse, verbose bool) error {
 			fmt.Print(string(modifiedJSON))
 		}
 	}
+}
+
+// printPermissionDecision выводит в stdout запрос подтверждения пользователя
+func printPermissionDecision(response *core.HookResponse) error {
+	// Claude Code читает решение из hookSpecificOutput при exit code 0
+	decision := map[string]any{
+		"hookSpecificOutput": map[string]any{
+			"hookEventName":            "PreToolUse",
+			"permissionDecision":       "ask",
+			"permissionDecisionReason": response.Message,
+		},
+	}
+	decisionJSON, err := json.Marshal(decision)
+	if err != nil {
+		return fmt.Errorf("failed to serialize permission decision: %w", err)
+	}
+	fmt.Println(string(decisionJSON))
+	return nil
+}
+
+// outputReport выводит нарушения хука в машиночитаемом формате в stderr
+// stdout остается за JSON ответами для Claude Code
+func outputReport(response *core.HookResponse, filePath string, format report.Format) error {
+	printModifiedToolInput(response)
+	if response.Action == core.HookActionAsk {
+		if err := printPermissionDecision(response); err != nil {
+			return err
+		}
+	}
+
+	hookReport := &report.Report{Version: Version}
+	if filePath != "" {
+		hookReport.Files = []string{filePath}
+	}
+	for _, violation := range response.Violations {
+		hookReport.Findings = append(hookReport.Findings, report.Finding{Path: filePath, Violation: violation})
+	}
+
+	return report.Write(os.Stderr, format, hookReport)
+}
+
+// outputResponse выводит ответ хука
+func outputResponse(response *core.HookResponse, verbose bool) error {
+	// Минимальное логирование согласно CLAUDE.md принципам
+	if verbose {
+		claudeHooksLogger.Debug("Hook response", "action", string(response.Action), "operation", "output_response")
+	}
+
+	printModifiedToolInput(response)
 
 	switch response.Action {
 	case core.HookActionBlock:
@@ -330,11 +455,15 @@ func outputResponse(response *core.HookResponse, verbose bool) error {
 				// Убрано избыточное логирование suggestions согласно CLAUDE.md
 			}
 		}
-	case core.HookActionWarn:
+	case core.HookActionWarn, core.HookActionNotice:
 		// Минимальное WARN логирование согласно CLAUDE.md
-		claudeHooksLogger.Warn("Hook warning", "message", response.Message)
+		claudeHooksLogger.Warn("Hook warning", "message", response.Message, "blocking", response.Action == core.HookActionWarn)
 
-		fmt.Fprintf(os.Stderr, "⚠️  WARNING: %s\n", response.Message)
+		if response.Action == core.HookActionNotice {
+			fmt.Fprintf(os.Stderr, "⚠️  WARNING (warn-only, not blocking): %s\n", response.Message)
+		} else {
+			fmt.Fprintf(os.Stderr, "⚠️  WARNING: %s\n", response.Message)
+		}
 		if len(response.Suggestions) > 0 {
 			fmt.Fprintf(os.Stderr, "💡 Suggestions:\n")
 			for _, suggestion := range response.Suggestions {
@@ -342,6 +471,12 @@ func outputResponse(response *core.HookResponse, verbose bool) error {
 				// Убрано избыточное логирование suggestions согласно CLAUDE.md
 			}
 		}
+	case core.HookActionAsk:
+		claudeHooksLogger.Warn("Hook requested user approval", "message", response.Message)
+
+		if err := printPermissionDecision(response); err != nil {
+			return err
+		}
 	case core.HookActionAllow:
 		// Минимальное INFO логирование только в verbose режиме
 		if verbose {
@@ -425,7 +560,7 @@ func showConfig(ctx context.Context) error {
 		return err
 	}
 
-	claudeHooksLogger.Info("📋 Current configuration", "config_file", configPath, "log_level", config.General.LogLevel, "timeout_ms", config.General.Timeout, "operation", "show_config", "component", "claude_hooks")
+	claudeHooksLogger.Info("📋 Current configuration", "config_file", configPath, "log_level", config.Logger.Level, "timeout_ms", config.General.Timeout, "operation", "show_config", "component", "claude_hooks")
 
 	claudeHooksLogger.Info("🔍 Validators", "operation", "show_config", "component", "claude_hooks")
 	for name, cfg := range config.Validators {
@@ -433,24 +568,138 @@ func showConfig(ctx context.Context) error {
 		if cfg.Enabled {
 			status = "enabled"
 		}
-		claudeHooksLogger.Info("Validator status", "name", name, "status", status, "enabled", cfg.Enabled, "operation", "show_config", "component", "claude_hooks")
+		claudeHooksLogger.Info("Validator status", "name", name, "status", status, "enabled", cfg.Enabled, "builtin_exemptions", cfg.UseBuiltinExemptions(), "operation", "show_config", "component", "claude_hooks")
+		if cfg.UseBuiltinExemptions() {
+			claudeHooksLogger.Info("Built-in exemptions", "name", name, "patterns", strings.Join(core.BuiltinExemptions(name), " "), "operation", "show_config", "component", "claude_hooks")
+		}
 	}
 
 	return nil
 }
 
-// validateConfigFile валидирует конфигурационный файл
+// validateConfigFile валидирует конфигурационный файл и печатает все найденные проблемы
 func validateConfigFile(ctx context.Context) error {
-	_, err := core.LoadConfig(configPath)
+	issues, changes, err := core.CheckConfigFile(configPath)
 	if err != nil {
 		claudeHooksLogger.Error("❌ Configuration validation failed", "error", err.Error(), "config_path", configPath, "operation", "validate_config_file", "component", "claude_hooks")
 		return err
 	}
 
+	displayPath := configPath
+	if displayPath == "" {
+		displayPath = "config.yaml"
+	}
+
+	// Устаревший формат загружается, но требует миграции
+	for _, change := range changes {
+		fmt.Fprintf(os.Stderr, "%s: deprecated: %s\n", displayPath, change.String())
+	}
+	if len(changes) > 0 {
+		fmt.Fprintf(os.Stderr, "%s: run 'claude-hooks config migrate' to upgrade to version %d\n", displayPath, core.CurrentConfigVersion)
+	}
+
+	if len(issues) > 0 {
+		for _, issue := range issues {
+			fmt.Fprintf(os.Stderr, "%s:%s\n", displayPath, issue.String())
+		}
+		claudeHooksLogger.Error("❌ Configuration validation failed", "problems", len(issues), "config_path", configPath, "operation", "validate_config_file", "component", "claude_hooks")
+		return fmt.Errorf("configuration has %d problem(s)", len(issues))
+	}
+
 	claudeHooksLogger.Info("✅ Configuration is valid", "config_path", configPath, "operation", "validate_config_file", "component", "claude_hooks")
 	return nil
 }
 
+// newConfigMigrateCmd создает команду миграции конфигурации на текущий формат
+func newConfigMigrateCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade configuration file to the current format",
+		Long: `Rewrites legacy configuration keys to the current schema (version ` + fmt.Sprint(core.CurrentConfigVersion) + `).
+Comments are preserved where possible, the original file is kept as <config>.bak
+and every transformation is reported.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return migrateConfigFile(cmd.OutOrStdout(), dryRun)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print migrated configuration without writing it")
+
+	return cmd
+}
+
+// configureRedaction маскирует секреты правилами validators.secrets загруженной конфигурации
+func configureRedaction(config *core.Config, logger core.Logger) error {
+	redactor, err := validators.NewSecretsRedactor(config.Validators["secrets"], logger)
+	if err != nil {
+		return fmt.Errorf("failed to create secrets redactor: %w", err)
+	}
+	core.SetRedactor(redactor)
+	return nil
+}
+
+// migrateConfigFile переводит конфигурационный файл в текущий формат
+func migrateConfigFile(w io.Writer, dryRun bool) error {
+	path := configPath
+	if path == "" {
+		path = core.DefaultConfigPath()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	migrated, changes, err := core.MigrateConfigData(data)
+	if err != nil {
+		return fmt.Errorf("failed to migrate %s: %w", path, err)
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintf(w, "%s is already at version %d\n", path, core.CurrentConfigVersion)
+		return nil
+	}
+
+	for _, change := range changes {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", path, change.String())
+	}
+
+	if issues := core.CheckConfig(migrated); len(issues) > 0 {
+		for _, issue := range issues {
+			fmt.Fprintf(os.Stderr, "%s: after migration: %s\n", path, issue.String())
+		}
+		fmt.Fprintf(os.Stderr, "%s: fix the remaining problems manually\n", path)
+	}
+
+	if dryRun {
+		_, err := w.Write(migrated)
+		return err
+	}
+
+	backupPath := path + ".bak"
+	if err := os.WriteFile(backupPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := os.WriteFile(path, migrated, 0644); err != nil {
+		return fmt.Errorf("failed to write migrated config: %w", err)
+	}
+
+	claudeHooksLogger.Info("✅ Configuration migrated", "config_path", path, "backup", backupPath, "changes", len(changes), "version", core.CurrentConfigVersion, "operation", "migrate_config", "component", "claude_hooks")
+	return nil
+}
+
+// printConfigSchema печатает JSON Schema конфигурации
+func printConfigSchema(w io.Writer) error {
+	data, err := json.MarshalIndent(core.ConfigSchema(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config schema: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
 // initConfig создает конфигурационный файл по умолчанию
 func initConfig(ctx context.Context) error {
 	if configPath == "" {
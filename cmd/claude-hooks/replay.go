@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aiseeq/claude-hooks/internal/replay"
+	"github.com/aiseeq/claude-hooks/internal/report"
+)
+
+// replayDecisions порядок вывода решений в сводке
+var replayDecisions = []replay.Decision{
+	replay.DecisionAllowed,
+	replay.DecisionWarned,
+	replay.DecisionBlocked,
+	replay.DecisionAsk,
+	replay.DecisionDenied,
+	replay.DecisionUnknown,
+}
+
+// newReplayCmd создает команду прогона транскриптов через текущую политику
+func newReplayCmd() *cobra.Command {
+	var showAll bool
+
+	cmd := &cobra.Command{
+		Use:   "replay <transcript.jsonl|dir>...",
+		Short: "Replay recorded tool calls through the current policy",
+		Long: `Extracts every tool call from Claude Code transcripts and feeds it through the
+PreToolUse processing with the current configuration. Reports which calls would be
+allowed, warned or blocked, per-rule counts and the calls whose outcome differs from
+the decision recorded in the transcript. Directories are searched for *.jsonl files.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := report.ParseFormat(reportFormat)
+			if err != nil {
+				return err
+			}
+			if format != report.FormatText && format != report.FormatJSON {
+				return fmt.Errorf("replay supports only %s and %s formats", report.FormatText, report.FormatJSON)
+			}
+			return runReplay(cmd.Context(), cmd.OutOrStdout(), args, format, showAll)
+		},
+	}
+
+	cmd.Flags().BoolVar(&showAll, "all", false, "List every call the current policy would warn, block or ask about")
+	addFormatFlag(cmd)
+
+	return cmd
+}
+
+// runReplay прогоняет транскрипты и печатает сводку
+func runReplay(ctx context.Context, w io.Writer, paths []string, format report.Format, showAll bool) error {
+	calls, err := replay.Load(paths)
+	if err != nil {
+		return err
+	}
+
+	engine, _, err := newScanEngine()
+	if err != nil {
+		return err
+	}
+
+	result := replay.Run(ctx, engine, calls)
+
+	if format == report.FormatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	printReplay(w, result, showAll)
+	return nil
+}
+
+// printReplay печатает результат replay в текстовом виде
+func printReplay(w io.Writer, result *replay.Result, showAll bool) {
+	fmt.Fprintf(w, "Replayed %d tool call(s)\n", len(result.Outcomes))
+	fmt.Fprintf(w, "  Current policy: %s\n", formatDecisions(result.Totals))
+	fmt.Fprintf(w, "  Recorded:       %s\n", formatDecisions(result.Recorded))
+	if result.Errors > 0 {
+		fmt.Fprintf(w, "  Errors:         %d (see --format json)\n", result.Errors)
+	}
+
+	if len(result.Rules) > 0 {
+		fmt.Fprintln(w, "\nRules:")
+		for _, rule := range result.Rules {
+			fmt.Fprintf(w, "  %5d call(s)  %5d violation(s)  %s\n", rule.Calls, rule.Violations, rule.Rule)
+		}
+	}
+
+	fmt.Fprintf(w, "\nDifferences from recorded decisions: %d\n", result.Changes)
+	for _, outcome := range result.Outcomes {
+		if outcome.Changed() || (showAll && outcome.Predicted != replay.DecisionAllowed && outcome.Predicted != replay.DecisionUnknown) {
+			printOutcome(w, outcome)
+		}
+	}
+}
+
+// printOutcome печатает один вызов с решениями и сработавшими правилами
+func printOutcome(w io.Writer, outcome replay.Outcome) {
+	marker := " "
+	if outcome.Changed() {
+		marker = "*"
+	}
+
+	var rules []string
+	seen := make(map[string]bool)
+	for _, violation := range outcome.Violations {
+		if id := replay.RuleID(violation); !seen[id] {
+			seen[id] = true
+			rules = append(rules, id)
+		}
+	}
+
+	fmt.Fprintf(w, "%s %s %s %s: %s -> %s", marker, outcome.Timestamp, outcome.ToolName, shortLine(outcome.Target), outcome.Recorded, outcome.Predicted)
+	if len(rules) > 0 {
+		fmt.Fprintf(w, " [%s]", strings.Join(rules, ", "))
+	}
+	fmt.Fprintln(w)
+}
+
+// formatDecisions форматирует счетчики решений в одну строку
+func formatDecisions(counts map[replay.Decision]int) string {
+	var parts []string
+	for _, decision := range replayDecisions {
+		if counts[decision] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[decision], decision))
+		}
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// shortLine возвращает первую строку, сокращенную для вывода в одну строку
+func shortLine(value string) string {
+	line, _, multiline := strings.Cut(value, "\n")
+	if runes := []rune(line); len(runes) > 80 {
+		return string(runes[:77]) + "..."
+	}
+	if multiline {
+		return line + " ..."
+	}
+	return line
+}
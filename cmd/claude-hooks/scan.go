@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aiseeq/claude-hooks/internal/gitdiff"
+	"github.com/aiseeq/claude-hooks/internal/report"
+	"github.com/aiseeq/claude-hooks/internal/scanner"
+)
+
+// newScanCmd создает команду офлайн проверки файлов тем же набором валидаторов
+func newScanCmd() *cobra.Command {
+	var (
+		diffRev    string
+		noBaseline bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "scan [paths...]",
+		Short: "Scan files with the hook validators",
+		Long: `Runs the same validators with the same configuration as the PreToolUse hook over
+files on disk, so agent policy can be enforced in CI and on human-authored code.
+With --diff only lines added since the given git revision are reported; untracked
+files that are not ignored count as fully added.
+Exits with code 1 when critical violations are found.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := args
+			if len(paths) == 0 {
+				paths = []string{"."}
+			}
+			format, err := report.ParseFormat(reportFormat)
+			if err != nil {
+				exitCode = 1
+				return err
+			}
+			code, err := runScan(cmd.Context(), cmd.OutOrStdout(), paths, diffRev, !noBaseline, format)
+			exitCode = code
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&diffRev, "diff", "", "Report only lines added since this git revision (e.g. origin/main)")
+	cmd.Flags().BoolVar(&noBaseline, "no-baseline", false, "Report violations recorded in the baseline too")
+	addFormatFlag(cmd)
+
+	return cmd
+}
+
+// runScan проверяет файлы и печатает найденные нарушения
+func runScan(ctx context.Context, w io.Writer, paths []string, diffRev string, useBaseline bool, format report.Format) (int, error) {
+	engine, _, err := newScanEngine()
+	if err != nil {
+		return 1, err
+	}
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		return 1, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	scanReport := &report.Report{Version: Version}
+	scan := func(path string, added map[int]bool) error {
+		file, err := scanner.LoadFile(path)
+		if err != nil || file == nil {
+			return err
+		}
+		file.SetProjectRoot(workDir)
+
+		violations, err := engine.ValidateFile(ctx, file)
+		if err != nil {
+			return fmt.Errorf("failed to validate %s: %w", path, err)
+		}
+		if useBaseline {
+			violations = engine.FilterBaseline(file, workDir, violations)
+		}
+
+		shownPath := displayPath(workDir, path)
+		scanReport.Files = append(scanReport.Files, shownPath)
+		for _, violation := range violations {
+			// Нарушения уровня файла (без строки) относятся к любому изменению
+			if added != nil && violation.Line > 0 && !added[violation.Line] {
+				continue
+			}
+			scanReport.Findings = append(scanReport.Findings, report.Finding{Path: shownPath, Violation: violation})
+		}
+		return nil
+	}
+
+	if diffRev != "" {
+		root, err := gitdiff.RepoRoot(ctx, workDir)
+		if err != nil {
+			return 1, err
+		}
+		diffs, err := gitdiff.Changed(ctx, workDir, diffRev, paths)
+		if err != nil {
+			return 1, err
+		}
+		for _, diff := range diffs {
+			if diff.Lines() == 0 {
+				continue
+			}
+			if err := scan(filepath.Join(root, diff.Path), diff.Added); err != nil {
+				return 1, err
+			}
+		}
+	} else {
+		err := scanner.Walk(paths, func(path string) error {
+			return scan(path, nil)
+		})
+		if err != nil {
+			return 1, err
+		}
+	}
+
+	if err := report.Write(w, format, scanReport); err != nil {
+		return 1, fmt.Errorf("failed to write report: %w", err)
+	}
+	if scanReport.Summary().Critical > 0 {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// displayPath возвращает путь относительно рабочей директории, если файл внутри нее
+func displayPath(workDir, path string) string {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(workDir, absPath); err == nil && filepath.IsLocal(rel) {
+		return rel
+	}
+	return absPath
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aiseeq/claude-hooks/internal/audit"
+	"github.com/aiseeq/claude-hooks/internal/core"
+	"github.com/aiseeq/claude-hooks/internal/secrets"
+)
+
+// newSecretsCmd создает команды управления секретами
+func newSecretsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "Manage the secrets validator",
+	}
+
+	var fromAudit, comment, expires string
+
+	allowCmd := &cobra.Command{
+		Use:   "allow [value|-]",
+		Short: "Allowlist a known-safe value by its SHA-256 fingerprint",
+		Long: `Adds the SHA-256 fingerprint of a known-safe value (a public test vector, a
+well-known burn address) to validators.secrets.allowlist. The value itself is never
+written to the configuration. Pass "-" to read the value from stdin and keep it out
+of the shell history, or --from-audit with an audit entry id (general.audit_file)
+to allowlist the values that blocked that operation. The allowlist also applies to
+credentials in Bash commands. The entry is inserted into the file as text, so
+comments and blank lines are kept; only a flow-style list (allowlist: []) makes
+the file get rewritten.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if comment == "" {
+				return fmt.Errorf("--comment is required: explain why the value is safe")
+			}
+			expiry, err := parseExpiry(expires, time.Now())
+			if err != nil {
+				return err
+			}
+
+			var fingerprints []string
+			switch {
+			case len(args) == 1 && fromAudit != "":
+				return fmt.Errorf("pass either a value or --from-audit, not both")
+			case len(args) == 1:
+				value, err := allowValue(args[0], cmd.InOrStdin())
+				if err != nil {
+					return err
+				}
+				if partialPrivateKey(value) {
+					return fmt.Errorf("a PEM header is the same for every private key: pass the whole block from BEGIN to END")
+				}
+				fingerprints = []string{secrets.Fingerprint(value)}
+			case fromAudit != "":
+				if fingerprints, err = auditFingerprints(fromAudit); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("pass a value, \"-\" for stdin or --from-audit <id>")
+			}
+
+			return runSecretsAllow(cmd.OutOrStdout(), fingerprints, comment, expiry)
+		},
+	}
+	allowCmd.Flags().StringVar(&fromAudit, "from-audit", "", "Allowlist secrets found in this audit entry")
+	allowCmd.Flags().StringVar(&comment, "comment", "", "Why the value is safe (required)")
+	allowCmd.Flags().StringVar(&expires, "expires", "90d", "Expiry as YYYY-MM-DD, a period like 30d, or never")
+
+	cmd.AddCommand(allowCmd)
+	return cmd
+}
+
+// parseExpiry переводит срок действия в дату allowlist, "" - бессрочно
+func parseExpiry(value string, now time.Time) (string, error) {
+	if value == "never" {
+		return "", nil
+	}
+	if _, err := time.Parse(core.AllowlistDateFormat, value); err == nil {
+		return value, nil
+	}
+	period, err := parsePeriod(value)
+	if err != nil || period == 0 {
+		return "", fmt.Errorf("invalid expiry: %q (expected YYYY-MM-DD, a period like 30d, or never)", value)
+	}
+	return now.Add(period).Format(core.AllowlistDateFormat), nil
+}
+
+// allowValue возвращает значение из аргумента или из stdin для "-"
+// Stdin читается целиком, чтобы можно было передать многострочный PEM ключ
+func allowValue(arg string, stdin io.Reader) (string, error) {
+	if arg != "-" {
+		return arg, nil
+	}
+	data, err := io.ReadAll(stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read value from stdin: %w", err)
+	}
+	value := strings.TrimRight(string(data), "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("empty value on stdin")
+	}
+	return value, nil
+}
+
+// partialPrivateKey проверяет, что в значении есть заголовок PEM без тела ключа:
+// такой отпечаток совпал бы у всех ключей
+func partialPrivateKey(value string) bool {
+	for _, finding := range secrets.NewRuleDetector(nil).Find(value) {
+		if finding.Fingerprint() == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// auditFingerprints возвращает отпечатки секретов из записи журнала аудита
+func auditFingerprints(id string) ([]string, error) {
+	config, err := core.LoadConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if config.General.AuditFile == "" {
+		return nil, fmt.Errorf("audit trail is disabled, set general.audit_file in the configuration")
+	}
+
+	entries, err := audit.Read(config.General.AuditFile, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	entry, err := audit.FindEntry(entries, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var fingerprints []string
+	seen := make(map[string]bool)
+	for _, hit := range entry.Hits {
+		// Учетные данные в Bash командах сверяются с тем же allowlist, что и секреты в файлах
+		if (hit.Validator == "secrets" || hit.Validator == "bash") && hit.Fingerprint != "" && !seen[hit.Fingerprint] {
+			seen[hit.Fingerprint] = true
+			fingerprints = append(fingerprints, hit.Fingerprint)
+		}
+	}
+	if len(fingerprints) == 0 {
+		return nil, fmt.Errorf("audit entry %s has no secrets findings", entry.ID)
+	}
+	return fingerprints, nil
+}
+
+// runSecretsAllow дописывает отпечатки в allowlist конфигурационного файла
+func runSecretsAllow(w io.Writer, fingerprints []string, comment, expires string) error {
+	path := configPath
+	if path == "" {
+		path = core.DefaultConfigPath()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	added := 0
+	for _, fingerprint := range fingerprints {
+		updated, ok, err := core.AddAllowlistEntry(data, "secrets", core.AllowlistEntry{
+			Fingerprint: fingerprint,
+			Comment:     comment,
+			Expires:     expires,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update %s: %w", path, err)
+		}
+		if !ok {
+			fmt.Fprintf(w, "%s is already allowlisted\n", fingerprint)
+			continue
+		}
+		data = updated
+		added++
+		fmt.Fprintf(w, "✅ Allowlisted %s\n", fingerprint)
+	}
+	if added == 0 {
+		return nil
+	}
+
+	if issues := core.CheckConfig(data); len(issues) > 0 {
+		return fmt.Errorf("updated configuration is invalid: %s", issues[0].String())
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	expiry := "never"
+	if expires != "" {
+		expiry = expires
+	}
+	fmt.Fprintf(w, "Updated validators.secrets.allowlist in %s (expires: %s)\n", path, expiry)
+	return nil
+}
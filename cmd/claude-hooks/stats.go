@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aiseeq/claude-hooks/internal/audit"
+	"github.com/aiseeq/claude-hooks/internal/core"
+	"github.com/aiseeq/claude-hooks/internal/report"
+)
+
+// newStatsCmd создает команду статистики срабатываний правил по журналу аудита
+func newStatsCmd() *cobra.Command {
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show rule hit rates from the audit trail",
+		Long: `Summarizes the PreToolUse audit trail (general.audit_file): how often each rule
+fired, separately for shadow and enforced hits. The shadow rate is the share of
+audited tool calls where a shadow rule fired; once it stays quiet the rule can be
+promoted to enforce.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := report.ParseFormat(reportFormat)
+			if err != nil {
+				return err
+			}
+			if format != report.FormatText && format != report.FormatJSON {
+				return fmt.Errorf("stats supports only %s and %s formats", report.FormatText, report.FormatJSON)
+			}
+			period, err := parsePeriod(since)
+			if err != nil {
+				return err
+			}
+			return runStats(cmd.OutOrStdout(), period, format)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "7d", "Period to summarize, e.g. 24h, 7d or 0 for the whole trail")
+	addFormatFlag(cmd)
+
+	return cmd
+}
+
+// parsePeriod разбирает длительность с поддержкой суффикса d (дни)
+func parsePeriod(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid period: %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	period, err := time.ParseDuration(value)
+	if err != nil || period < 0 {
+		return 0, fmt.Errorf("invalid period: %q", value)
+	}
+	return period, nil
+}
+
+// runStats читает журнал аудита и печатает статистику правил
+func runStats(w io.Writer, period time.Duration, format report.Format) error {
+	config, err := core.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if config.General.AuditFile == "" {
+		return fmt.Errorf("audit trail is disabled, set general.audit_file in the configuration")
+	}
+
+	var since time.Time
+	if period > 0 {
+		since = time.Now().Add(-period)
+	}
+	entries, err := audit.Read(config.General.AuditFile, since)
+	if err != nil {
+		return err
+	}
+	stats := audit.Summarize(entries)
+
+	if format == report.FormatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stats)
+	}
+
+	if stats.Calls == 0 {
+		fmt.Fprintf(w, "No audited tool calls in %s\n", config.General.AuditFile)
+		return nil
+	}
+
+	fmt.Fprintf(w, "Audited %d tool call(s) from %s to %s\n\n", stats.Calls, stats.From.Local().Format(time.DateTime), stats.To.Local().Format(time.DateTime))
+	fmt.Fprintf(w, "%-45s %-10s %8s %8s %9s  %s\n", "RULE", "MODE", "SHADOW", "ENFORCED", "RATE", "LAST HIT")
+	for _, rule := range stats.Rules {
+		fmt.Fprintf(w, "%-45s %-10s %8d %8d %8.2f%%  %s\n",
+			rule.Rule,
+			currentMode(config, rule),
+			rule.ShadowHits,
+			rule.Enforced,
+			stats.ShadowRate(rule)*100,
+			rule.LastHit.Local().Format(time.DateTime),
+		)
+	}
+	return nil
+}
+
+// currentMode возвращает режим правила по текущей конфигурации
+func currentMode(config *core.Config, rule audit.RuleStats) string {
+	if validator, ok := config.Validators[rule.Validator]; ok {
+		return validator.RuleMode(rule.Type)
+	}
+	if tool, ok := config.Tools[rule.Validator]; ok {
+		return tool.RuleMode(rule.Type)
+	}
+	return core.ModeEnforce
+}
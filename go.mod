@@ -3,6 +3,7 @@ module github.com/aiseeq/claude-hooks
 go 1.21
 
 require (
+	github.com/bmatcuk/doublestar/v4 v4.6.1
 	github.com/spf13/cobra v1.8.0
 	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,236 @@
+package audit
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+)
+
+// Hit сработавшее правило в записи журнала
+// Текст сообщения не сохраняется: журнал не должен содержать фрагменты кода
+type Hit struct {
+	Validator string     `json:"validator,omitempty"`
+	Type      string     `json:"type"`
+	Severity  core.Level `json:"severity"`
+	Line      int        `json:"line,omitempty"`
+	Shadow    bool       `json:"shadow,omitempty"`
+	// Отпечаток найденного секрета для claude-hooks secrets allow --from-audit
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// Rule возвращает идентификатор правила validator/type
+func (h Hit) Rule() string {
+	if h.Validator == "" {
+		return h.Type
+	}
+	return h.Validator + "/" + h.Type
+}
+
+// Entry запись журнала об одном решении PreToolUse
+type Entry struct {
+	ID        string          `json:"id,omitempty"`
+	Time      time.Time       `json:"time"`
+	SessionID string          `json:"session_id,omitempty"`
+	Tool      string          `json:"tool"`
+	File      string          `json:"file,omitempty"`
+	Action    core.HookAction `json:"action"`
+	Hits      []Hit           `json:"hits,omitempty"`
+}
+
+// NewEntry строит запись по входным данным и ответу хука
+// Команды Bash не сохраняются, так как могут содержать секреты
+func NewEntry(input *core.ToolInput, response *core.HookResponse) Entry {
+	entry := Entry{
+		ID:        newEntryID(),
+		Time:      response.Timestamp,
+		SessionID: input.SessionID,
+		Tool:      input.ToolName,
+		File:      input.FilePath,
+		Action:    response.Action,
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	for _, violation := range append(append([]core.Violation{}, response.Violations...), response.Shadow...) {
+		entry.Hits = append(entry.Hits, Hit{
+			Validator:   violation.Validator,
+			Type:        violation.Type,
+			Severity:    violation.Severity,
+			Line:        violation.Line,
+			Shadow:      violation.Shadow,
+			Fingerprint: violation.Fingerprint,
+		})
+	}
+	return entry
+}
+
+// newEntryID создает короткий случайный идентификатор записи
+func newEntryID() string {
+	id := make([]byte, 6)
+	if _, err := rand.Read(id); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(id)
+}
+
+// FindEntry ищет запись по идентификатору или его однозначному префиксу
+func FindEntry(entries []Entry, id string) (Entry, error) {
+	var found []Entry
+	for _, entry := range entries {
+		if entry.ID == id {
+			return entry, nil
+		}
+		if id != "" && strings.HasPrefix(entry.ID, id) {
+			found = append(found, entry)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return Entry{}, fmt.Errorf("audit entry %q not found", id)
+	case 1:
+		return found[0], nil
+	}
+	return Entry{}, fmt.Errorf("audit entry id %q is ambiguous (%d entries)", id, len(found))
+}
+
+// Append дописывает запись в JSONL журнал
+func Append(path string, entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create audit directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file: %w", err)
+	}
+	defer f.Close()
+
+	// Одна запись одним write, чтобы параллельные хуки не перемешивали строки
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return nil
+}
+
+// Read читает записи журнала не старше since
+// Поврежденные строки (например, оборванные при записи) пропускаются
+func Read(path string, since time.Time) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if entry.Time.Before(since) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit file: %w", err)
+	}
+
+	return entries, nil
+}
+
+// RuleStats статистика срабатываний одного правила
+type RuleStats struct {
+	Rule        string    `json:"rule"`
+	Validator   string    `json:"validator,omitempty"`
+	Type        string    `json:"type"`
+	ShadowHits  int       `json:"shadow_hits"`
+	ShadowCalls int       `json:"shadow_calls"`
+	Enforced    int       `json:"enforced_hits"`
+	LastHit     time.Time `json:"last_hit"`
+}
+
+// Stats сводка журнала за период
+type Stats struct {
+	Calls int         `json:"calls"`
+	From  time.Time   `json:"from,omitempty"`
+	To    time.Time   `json:"to,omitempty"`
+	Rules []RuleStats `json:"rules"`
+}
+
+// ShadowRate доля проверенных вызовов, на которых сработало shadow правило
+func (s *Stats) ShadowRate(rule RuleStats) float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	return float64(rule.ShadowCalls) / float64(s.Calls)
+}
+
+// Summarize считает срабатывания правил по записям журнала
+func Summarize(entries []Entry) *Stats {
+	stats := &Stats{Calls: len(entries), Rules: []RuleStats{}}
+	rules := make(map[string]*RuleStats)
+
+	for _, entry := range entries {
+		if stats.From.IsZero() || entry.Time.Before(stats.From) {
+			stats.From = entry.Time
+		}
+		if entry.Time.After(stats.To) {
+			stats.To = entry.Time
+		}
+
+		shadowSeen := make(map[string]bool)
+		for _, hit := range entry.Hits {
+			id := hit.Rule()
+			rule, ok := rules[id]
+			if !ok {
+				rule = &RuleStats{Rule: id, Validator: hit.Validator, Type: hit.Type}
+				rules[id] = rule
+			}
+			if hit.Shadow {
+				rule.ShadowHits++
+				if !shadowSeen[id] {
+					shadowSeen[id] = true
+					rule.ShadowCalls++
+				}
+			} else {
+				rule.Enforced++
+			}
+			if entry.Time.After(rule.LastHit) {
+				rule.LastHit = entry.Time
+			}
+		}
+	}
+
+	for _, rule := range rules {
+		stats.Rules = append(stats.Rules, *rule)
+	}
+	sort.Slice(stats.Rules, func(i, j int) bool {
+		if stats.Rules[i].ShadowCalls != stats.Rules[j].ShadowCalls {
+			return stats.Rules[i].ShadowCalls > stats.Rules[j].ShadowCalls
+		}
+		return stats.Rules[i].Rule < stats.Rules[j].Rule
+	})
+
+	return stats
+}
@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+)
+
+func TestAppendRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "audit.jsonl")
+	now := time.Now()
+
+	old := Entry{Time: now.Add(-48 * time.Hour), Tool: "Write", Action: core.HookActionAllow}
+	recent := NewEntry(
+		&core.ToolInput{SessionID: "s1", ToolName: "Bash", Command: "export TOKEN=secret"},
+		&core.HookResponse{
+			Action:    core.HookActionAllow,
+			Timestamp: now,
+			Shadow:    []core.Violation{{Validator: "bash", Type: "dangerous_bash_command", Severity: core.LevelCritical, Shadow: true}},
+		},
+	)
+	for _, entry := range []Entry{old, recent} {
+		if err := Append(path, entry); err != nil {
+			t.Fatalf("append failed: %v", err)
+		}
+	}
+
+	// Оборванная строка не должна ломать чтение
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"time":"2`)
+	f.Close()
+
+	entries, err := Read(path, now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].SessionID != "s1" || len(entries[0].Hits) != 1 || !entries[0].Hits[0].Shadow {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "secret") {
+		t.Error("audit trail must not store commands")
+	}
+
+	if missing, err := Read(filepath.Join(t.TempDir(), "none.jsonl"), time.Time{}); err != nil || missing != nil {
+		t.Errorf("missing audit file should be empty, got %v, %v", missing, err)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	shadow := Hit{Validator: "secrets", Type: "wallet_address", Severity: core.LevelCritical, Shadow: true}
+	enforced := Hit{Validator: "runtime_exit", Type: "runtime_exit_usage", Severity: core.LevelCritical}
+
+	entries := []Entry{
+		{Time: base, Hits: []Hit{shadow, shadow}},
+		{Time: base.Add(time.Hour), Hits: []Hit{enforced}},
+		{Time: base.Add(2 * time.Hour)},
+		{Time: base.Add(3 * time.Hour), Hits: []Hit{shadow}},
+	}
+
+	stats := Summarize(entries)
+	if stats.Calls != 4 || !stats.From.Equal(base) || !stats.To.Equal(base.Add(3*time.Hour)) {
+		t.Fatalf("unexpected period: %+v", stats)
+	}
+	if len(stats.Rules) != 2 || stats.Rules[0].Rule != "secrets/wallet_address" {
+		t.Fatalf("unexpected rules: %+v", stats.Rules)
+	}
+
+	rule := stats.Rules[0]
+	if rule.ShadowHits != 3 || rule.ShadowCalls != 2 || rule.Enforced != 0 {
+		t.Errorf("unexpected shadow counts: %+v", rule)
+	}
+	if rate := stats.ShadowRate(rule); rate != 0.5 {
+		t.Errorf("expected shadow rate 0.5, got %v", rate)
+	}
+	if !rule.LastHit.Equal(base.Add(3 * time.Hour)) {
+		t.Errorf("unexpected last hit: %v", rule.LastHit)
+	}
+	if stats.Rules[1].Enforced != 1 || stats.ShadowRate(stats.Rules[1]) != 0 {
+		t.Errorf("unexpected enforced rule: %+v", stats.Rules[1])
+	}
+}
+
+func TestFindEntry(t *testing.T) {
+	entry := NewEntry(
+		&core.ToolInput{ToolName: "Write", FilePath: "config.go"},
+		&core.HookResponse{
+			Action:     core.HookActionBlock,
+			Violations: []core.Violation{{Validator: "secrets", Type: "hardcoded_jwt", Severity: core.LevelCritical, Fingerprint: "sha256:abc"}},
+		},
+	)
+	if len(entry.ID) != 12 || entry.Hits[0].Fingerprint != "sha256:abc" {
+		t.Fatalf("entry should have an id and hit fingerprints: %+v", entry)
+	}
+
+	if found, err := FindEntry([]Entry{{ID: "a1b2c3"}, entry}, entry.ID); err != nil || found.ID != entry.ID {
+		t.Errorf("exact id: got %+v, %v", found, err)
+	}
+
+	entries := []Entry{{ID: "a1b2c3"}, {ID: "a1ffff"}}
+	if found, err := FindEntry(entries, "a1b"); err != nil || found.ID != "a1b2c3" {
+		t.Errorf("unique prefix: got %+v, %v", found, err)
+	}
+	if _, err := FindEntry(entries, "a1"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("ambiguous prefix should fail, got %v", err)
+	}
+	if _, err := FindEntry(entries, "zz"); err == nil {
+		t.Error("unknown id should fail")
+	}
+}
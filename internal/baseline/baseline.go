@@ -0,0 +1,200 @@
+package baseline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultFile имя файла baseline в корне проекта
+const DefaultFile = ".claude-hooks-baseline.json"
+
+// formatVersion версия формата файла baseline
+const formatVersion = 1
+
+// Entry известное нарушение, зафиксированное в baseline
+// Содержимое строки хранится только в виде хеша, чтобы не сохранять секреты
+type Entry struct {
+	Validator   string `json:"validator"`
+	Type        string `json:"type"`
+	Path        string `json:"path"`
+	Fingerprint string `json:"fingerprint"`
+	Count       int    `json:"count"`
+}
+
+// Baseline набор известных нарушений
+type Baseline struct {
+	Version int     `json:"version"`
+	Entries []Entry `json:"entries"`
+}
+
+// New создает пустой baseline
+func New() *Baseline {
+	return &Baseline{Version: formatVersion}
+}
+
+// Load читает baseline из файла
+func Load(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	if b.Version > formatVersion {
+		return nil, fmt.Errorf("baseline %s has unsupported version %d", path, b.Version)
+	}
+
+	return &b, nil
+}
+
+// Save записывает baseline в стабильном порядке, чтобы diff в git был минимальным
+func (b *Baseline) Save(path string) error {
+	b.sort()
+	if b.Entries == nil {
+		b.Entries = []Entry{}
+	}
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create baseline directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
+
+// Add фиксирует нарушение в baseline
+func (b *Baseline) Add(validator, violationType, relPath, line string) {
+	relPath = filepath.ToSlash(relPath)
+	fingerprint := Fingerprint(validator, violationType, relPath, line)
+	for i := range b.Entries {
+		if b.Entries[i].Fingerprint == fingerprint {
+			b.Entries[i].Count++
+			return
+		}
+	}
+	b.Entries = append(b.Entries, Entry{
+		Validator:   validator,
+		Type:        violationType,
+		Path:        relPath,
+		Fingerprint: fingerprint,
+		Count:       1,
+	})
+}
+
+// Total возвращает общее количество зафиксированных нарушений
+func (b *Baseline) Total() int {
+	total := 0
+	for _, entry := range b.Entries {
+		total += entry.Count
+	}
+	return total
+}
+
+// Prune оставляет только нарушения, которые все еще присутствуют в current
+// Возвращает количество удаленных нарушений
+func (b *Baseline) Prune(current *Baseline) int {
+	present := make(map[string]int, len(current.Entries))
+	for _, entry := range current.Entries {
+		present[entry.Fingerprint] += entry.Count
+	}
+
+	removed := 0
+	kept := b.Entries[:0]
+	for _, entry := range b.Entries {
+		count := entry.Count
+		if count > present[entry.Fingerprint] {
+			count = present[entry.Fingerprint]
+		}
+		removed += entry.Count - count
+		if count > 0 {
+			entry.Count = count
+			kept = append(kept, entry)
+		}
+	}
+	b.Entries = kept
+
+	return removed
+}
+
+// Matcher сопоставляет нарушения с baseline
+// Каждая запись поглощает не больше нарушений, чем было зафиксировано
+type Matcher struct {
+	remaining map[string]int
+}
+
+// Matcher создает сопоставитель для одного прохода проверки
+func (b *Baseline) Matcher() *Matcher {
+	remaining := make(map[string]int, len(b.Entries))
+	for _, entry := range b.Entries {
+		remaining[entry.Fingerprint] += entry.Count
+	}
+	return &Matcher{remaining: remaining}
+}
+
+// Match проверяет является ли нарушение известным
+func (m *Matcher) Match(validator, violationType, relPath, line string) bool {
+	fingerprint := Fingerprint(validator, violationType, filepath.ToSlash(relPath), line)
+	if m.remaining[fingerprint] == 0 {
+		return false
+	}
+	m.remaining[fingerprint]--
+	return true
+}
+
+// Fingerprint вычисляет отпечаток нарушения по правилу, пути и нормализованной строке
+// Номер строки не учитывается, поэтому правки выше по файлу не ломают baseline
+func Fingerprint(validator, violationType, relPath, line string) string {
+	hash := sha256.New()
+	for _, part := range []string{validator, violationType, relPath, NormalizeLine(line)} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil))
+}
+
+// NormalizeLine убирает различия в пробелах и отступах
+func NormalizeLine(line string) string {
+	return strings.Join(strings.Fields(line), " ")
+}
+
+// Resolve возвращает путь к файлу baseline с учетом настройки и директории проекта
+func Resolve(configured, projectDir string) string {
+	if configured == "" {
+		configured = DefaultFile
+	}
+	if filepath.IsAbs(configured) {
+		return configured
+	}
+	return filepath.Join(projectDir, configured)
+}
+
+// sort упорядочивает записи по пути, правилу и отпечатку
+func (b *Baseline) sort() {
+	sort.Slice(b.Entries, func(i, j int) bool {
+		a, c := b.Entries[i], b.Entries[j]
+		if a.Path != c.Path {
+			return a.Path < c.Path
+		}
+		if a.Validator != c.Validator {
+			return a.Validator < c.Validator
+		}
+		if a.Type != c.Type {
+			return a.Type < c.Type
+		}
+		return a.Fingerprint < c.Fingerprint
+	})
+}
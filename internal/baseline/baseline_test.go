@@ -0,0 +1,86 @@
+package baseline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFingerprint_IgnoresWhitespace(t *testing.T) {
+	a := Fingerprint("secrets", "hardcoded_jwt", "internal/a.go", "\ttoken :=  \"eyJ\"")
+	b := Fingerprint("secrets", "hardcoded_jwt", "internal/a.go", "token := \"eyJ\"   ")
+	if a != b {
+		t.Errorf("fingerprints should ignore indentation and spacing: %s != %s", a, b)
+	}
+
+	tests := []struct {
+		name      string
+		validator string
+		vType     string
+		path      string
+		line      string
+	}{
+		{"different validator", "runtime_exit", "hardcoded_jwt", "internal/a.go", "token := \"eyJ\""},
+		{"different type", "secrets", "wallet_address", "internal/a.go", "token := \"eyJ\""},
+		{"different path", "secrets", "hardcoded_jwt", "internal/b.go", "token := \"eyJ\""},
+		{"different content", "secrets", "hardcoded_jwt", "internal/a.go", "token := \"eyK\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if Fingerprint(tt.validator, tt.vType, tt.path, tt.line) == a {
+				t.Error("fingerprint should differ")
+			}
+		})
+	}
+}
+
+func TestMatcher_ConsumesCounts(t *testing.T) {
+	b := New()
+	b.Add("secrets", "hardcoded_jwt", "a.go", "x := 1")
+	b.Add("secrets", "hardcoded_jwt", "a.go", "x := 1")
+
+	matcher := b.Matcher()
+	for i := 0; i < 2; i++ {
+		if !matcher.Match("secrets", "hardcoded_jwt", "a.go", "  x := 1") {
+			t.Fatalf("match %d should be absorbed by baseline", i+1)
+		}
+	}
+	if matcher.Match("secrets", "hardcoded_jwt", "a.go", "x := 1") {
+		t.Error("third identical violation is new and should not match")
+	}
+}
+
+func TestPruneAndSave(t *testing.T) {
+	known := New()
+	known.Add("secrets", "hardcoded_jwt", "a.go", "x := 1")
+	known.Add("secrets", "hardcoded_jwt", "a.go", "x := 1")
+	known.Add("runtime_exit", "runtime_exit_usage", "b.go", "fail()")
+
+	current := New()
+	current.Add("secrets", "hardcoded_jwt", "a.go", "x := 1")
+
+	if removed := known.Prune(current); removed != 2 {
+		t.Errorf("expected 2 removed violations, got %d", removed)
+	}
+	if known.Total() != 1 || len(known.Entries) != 1 {
+		t.Fatalf("expected one remaining entry, got %+v", known.Entries)
+	}
+
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	if err := known.Save(path); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "x := 1") {
+		t.Error("baseline must not store line content")
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if !loaded.Matcher().Match("secrets", "hardcoded_jwt", "a.go", "x := 1") {
+		t.Error("loaded baseline should match recorded violation")
+	}
+}
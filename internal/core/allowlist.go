@@ -0,0 +1,243 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AllowlistDateFormat формат даты истечения записи allowlist
+const AllowlistDateFormat = "2006-01-02"
+
+// fingerprintPattern отпечаток значения: sha256:<64 hex>
+var fingerprintPattern = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// AllowlistEntry известное безопасное значение (тестовый вектор, публичный адрес)
+// Хранится только SHA-256 отпечаток, само значение в конфигурацию не попадает
+type AllowlistEntry struct {
+	Fingerprint string `yaml:"fingerprint"`
+	Comment     string `yaml:"comment,omitempty"`
+	Expires     string `yaml:"expires,omitempty"` // YYYY-MM-DD, пусто - бессрочно
+}
+
+// Active проверяет, действует ли запись на момент now: срок действия включает день истечения
+func (e AllowlistEntry) Active(now time.Time) bool {
+	if e.Expires == "" {
+		return true
+	}
+	expires, err := time.ParseInLocation(AllowlistDateFormat, e.Expires, now.Location())
+	if err != nil {
+		return false
+	}
+	return now.Before(expires.AddDate(0, 0, 1))
+}
+
+// Allowlist записи allowlist по отпечатку
+type Allowlist map[string]AllowlistEntry
+
+// NewAllowlist индексирует записи по отпечатку
+func NewAllowlist(entries []AllowlistEntry) Allowlist {
+	allowlist := make(Allowlist, len(entries))
+	for _, entry := range entries {
+		allowlist[entry.Fingerprint] = entry
+	}
+	return allowlist
+}
+
+// Match возвращает запись для отпечатка, если она действует на момент now
+func (a Allowlist) Match(fingerprint string, now time.Time) (AllowlistEntry, bool) {
+	entry, ok := a[fingerprint]
+	if !ok || fingerprint == "" || !entry.Active(now) {
+		return AllowlistEntry{}, false
+	}
+	return entry, true
+}
+
+// validateAllowlist проверяет формат отпечатков и дат записей allowlist
+func validateAllowlist(entries []AllowlistEntry, path string, report func(path, message string)) {
+	for _, entry := range entries {
+		if !fingerprintPattern.MatchString(entry.Fingerprint) {
+			report(path, fmt.Sprintf("invalid fingerprint: %q (expected sha256:<64 hex>)", entry.Fingerprint))
+		}
+		if entry.Expires != "" {
+			if _, err := time.Parse(AllowlistDateFormat, entry.Expires); err != nil {
+				report(path, fmt.Sprintf("invalid expires date: %q (expected YYYY-MM-DD)", entry.Expires))
+			}
+		}
+	}
+}
+
+// AddAllowlistEntry добавляет запись в validators.<validator>.allowlist YAML конфигурации;
+// false означает, что отпечаток уже есть в списке
+// Запись вставляется в текст файла, поэтому комментарии, пустые строки и форматирование сохраняются.
+// Файл переписывается целиком, только если allowlist записан в flow стиле или пуст (allowlist: [])
+func AddAllowlistEntry(data []byte, validator string, entry AllowlistEntry) ([]byte, bool, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, false, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	item, err := encodeAllowlistItem(entry)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Ищем самое глубокое из validators -> <validator> -> allowlist, которое уже есть в файле
+	path := []string{"validators", validator, "allowlist"}
+	var parent *yaml.Node
+	if len(root.Content) > 0 {
+		parent = root.Content[0]
+	}
+	depth := 0
+	for parent != nil && isBlockMapping(parent) && depth < len(path) {
+		_, value := mappingEntry(parent, path[depth])
+		if value == nil {
+			break
+		}
+		if depth == len(path)-1 {
+			if value.Kind != yaml.SequenceNode || value.Style&yaml.FlowStyle != 0 || len(value.Content) == 0 {
+				return reencodeWithAllowlistEntry(&root, validator, item)
+			}
+			for _, existing := range value.Content {
+				if _, fingerprint := mappingEntry(existing, "fingerprint"); fingerprint != nil && fingerprint.Value == entry.Fingerprint {
+					return data, false, nil
+				}
+			}
+			// Новый элемент после последнего элемента списка с тем же отступом
+			last := value.Content[len(value.Content)-1]
+			return insertLines(data, blockEnd(data, last.Line, value.Column-1), indentLines(item, value.Column-1)), true, nil
+		}
+		parent = value
+		depth++
+	}
+	if parent == nil || !isBlockMapping(parent) {
+		return reencodeWithAllowlistEntry(&root, validator, item)
+	}
+
+	// Недостающие ключи пути добавляются после последнего ключа найденного отображения
+	indent := parent.Content[0].Column - 1
+	var fragment strings.Builder
+	for i, key := range path[depth:] {
+		fmt.Fprintf(&fragment, "%s%s:\n", strings.Repeat(" ", indent+2*i), key)
+	}
+	fragment.WriteString(indentLines(item, indent+2*(len(path)-depth)))
+
+	lastKey := parent.Content[len(parent.Content)-2]
+	return insertLines(data, blockEnd(data, lastKey.Line, lastKey.Column-1), fragment.String()), true, nil
+}
+
+// encodeAllowlistItem кодирует запись как элемент YAML списка: "- fingerprint: ...\n  comment: ...\n"
+func encodeAllowlistItem(entry AllowlistEntry) (string, error) {
+	var item yaml.Node
+	if err := item.Encode(entry); err != nil {
+		return "", fmt.Errorf("failed to encode allowlist entry: %w", err)
+	}
+	// Дата в кавычках, чтобы YAML не считал ее timestamp
+	if _, expires := mappingEntry(&item, "expires"); expires != nil {
+		expires.Style = yaml.DoubleQuotedStyle
+	}
+	text, err := encodeYAML(&yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{&item}})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode allowlist entry: %w", err)
+	}
+	return string(text), nil
+}
+
+// reencodeWithAllowlistEntry добавляет элемент через дерево YAML и кодирует файл заново
+// Используется для пустого файла и flow стиля, где вставка в текст невозможна
+func reencodeWithAllowlistEntry(root *yaml.Node, validator, item string) ([]byte, bool, error) {
+	if root.Kind == 0 || len(root.Content) == 0 {
+		*root = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+
+	var itemNode yaml.Node
+	if err := yaml.Unmarshal([]byte(item), &itemNode); err != nil {
+		return nil, false, fmt.Errorf("failed to encode allowlist entry: %w", err)
+	}
+
+	validatorNode := ensureMapping(ensureMapping(root.Content[0], "validators"), validator)
+	_, list := mappingEntry(validatorNode, "allowlist")
+	if list == nil || list.Kind != yaml.SequenceNode {
+		removeMappingEntry(validatorNode, "allowlist")
+		list = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		validatorNode.Content = append(validatorNode.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "allowlist"},
+			list,
+		)
+	}
+	list.Style = 0
+	list.Content = append(list.Content, itemNode.Content[0].Content...)
+
+	data, err := encodeYAML(root)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode config: %w", err)
+	}
+	return data, true, nil
+}
+
+// encodeYAML кодирует узел с отступом 2
+func encodeYAML(node *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// isBlockMapping проверяет, что узел - непустое отображение в блочном стиле
+func isBlockMapping(node *yaml.Node) bool {
+	return node.Kind == yaml.MappingNode && node.Style&yaml.FlowStyle == 0 && len(node.Content) > 0
+}
+
+// blockEnd возвращает номер последней строки блока, который начинается на строке line (с 1):
+// следующие строки блока непустые и имеют отступ больше indent
+func blockEnd(data []byte, line, indent int) int {
+	lines := strings.Split(string(data), "\n")
+	end := line
+	for i := line; i < len(lines); i++ {
+		text := lines[i]
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || len(text)-len(trimmed) <= indent {
+			break
+		}
+		end = i + 1
+	}
+	return end
+}
+
+// insertLines вставляет текст после строки line (с 1)
+func insertLines(data []byte, line int, text string) []byte {
+	lines := strings.SplitAfter(string(data), "\n")
+	var buf strings.Builder
+	for i, current := range lines {
+		buf.WriteString(current)
+		if i == line-1 {
+			if !strings.HasSuffix(current, "\n") {
+				buf.WriteString("\n")
+			}
+			buf.WriteString(text)
+		}
+	}
+	return []byte(buf.String())
+}
+
+// indentLines добавляет отступ к каждой непустой строке
+func indentLines(text string, indent int) string {
+	prefix := strings.Repeat(" ", indent)
+	lines := strings.SplitAfter(text, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "")
+}
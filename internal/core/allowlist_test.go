@@ -0,0 +1,145 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testFingerprint = "sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+
+func TestAllowlistEntry_Active(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expires string
+		want    bool
+	}{
+		{"", true},
+		{"2026-03-11", true},
+		{"2026-03-10", true},
+		{"2026-03-09", false},
+		{"soon", false},
+	}
+	for _, tt := range tests {
+		if got := (AllowlistEntry{Fingerprint: testFingerprint, Expires: tt.expires}).Active(now); got != tt.want {
+			t.Errorf("Active() with expires %q = %v, want %v", tt.expires, got, tt.want)
+		}
+	}
+}
+
+func TestCheckConfig_Allowlist(t *testing.T) {
+	data := `version: 2
+logger:
+  level: "info"
+  output: "stderr"
+validators:
+  secrets:
+    enabled: true
+    allowlist:
+      - fingerprint: "` + testFingerprint + `"
+        comment: RFC 7519 example token
+        expires: 2026-12-31
+      - fingerprint: "hunter2"
+      - fingerprint: "` + testFingerprint + `"
+        expires: "31.12.2026"
+`
+
+	issues := CheckConfig([]byte(data))
+	if len(issues) != 2 {
+		t.Fatalf("expected fingerprint and date issues, got %v", issues)
+	}
+	if !strings.Contains(issues[0].Message, "invalid fingerprint") || !strings.Contains(issues[1].Message, "invalid expires date") {
+		t.Errorf("unexpected issues: %v", issues)
+	}
+}
+
+func TestAddAllowlistEntry(t *testing.T) {
+	data := []byte(`version: 2
+logger:
+  level: "info"
+  output: "stderr"
+# Validators
+validators:
+  secrets:
+    enabled: true # keep
+`)
+	entry := AllowlistEntry{Fingerprint: testFingerprint, Comment: "RFC 7519 example", Expires: "2026-12-31"}
+
+	updated, added, err := AddAllowlistEntry(data, "secrets", entry)
+	if err != nil || !added {
+		t.Fatalf("expected entry to be added, got %v, %v", added, err)
+	}
+	text := string(updated)
+	for _, want := range []string{"# Validators", "enabled: true # keep", "allowlist:", "fingerprint: " + testFingerprint, `expires: "2026-12-31"`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("updated config should contain %q:\n%s", want, text)
+		}
+	}
+
+	config, _, issues := parseConfig(updated)
+	if len(issues) != 0 {
+		t.Fatalf("updated config should be valid, got %v", issues)
+	}
+	if len(config.Validators["secrets"].Allowlist) != 1 || config.Validators["secrets"].Allowlist[0] != entry {
+		t.Fatalf("updated config should parse with the entry, got %+v, %v", config.Validators["secrets"].Allowlist, issues)
+	}
+
+	if _, added, err := AddAllowlistEntry(updated, "secrets", entry); err != nil || added {
+		t.Errorf("duplicate fingerprint should not be added, got %v, %v", added, err)
+	}
+}
+
+func TestAddAllowlistEntry_KeepsFormatting(t *testing.T) {
+	entry := AllowlistEntry{Fingerprint: testFingerprint, Comment: "test vector"}
+	item := "- fingerprint: " + testFingerprint + "\n  comment: test vector\n"
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			"new allowlist in validator",
+			"version: 2\n\nvalidators:\n  secrets:\n    enabled: true\n\n    exception_paths:\n      - \"*.md\"\n\n  # runtime checks\n  runtime_exit:\n    enabled: true\n",
+			"version: 2\n\nvalidators:\n  secrets:\n    enabled: true\n\n    exception_paths:\n      - \"*.md\"\n    allowlist:\n" + indentLines(item, 6) + "\n  # runtime checks\n  runtime_exit:\n    enabled: true\n",
+		},
+		{
+			"existing allowlist",
+			"validators:\n  secrets:\n    allowlist:\n      - fingerprint: sha256:other\n        comment: first\n\n    enabled: true\n",
+			"validators:\n  secrets:\n    allowlist:\n      - fingerprint: sha256:other\n        comment: first\n" + indentLines(item, 6) + "\n    enabled: true\n",
+		},
+		{
+			"new validators section",
+			"version: 2\n\nlogger:\n  level: info\n# end",
+			"version: 2\n\nlogger:\n  level: info\nvalidators:\n  secrets:\n    allowlist:\n" + indentLines(item, 6) + "# end",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, added, err := AddAllowlistEntry([]byte(tt.data), "secrets", entry)
+			if err != nil || !added {
+				t.Fatalf("expected entry to be added, got %v, %v", added, err)
+			}
+			if string(updated) != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", updated, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddAllowlistEntry_FlowStyle(t *testing.T) {
+	data := []byte("version: 2\nlogger:\n  level: \"info\"\n  output: \"stderr\"\nvalidators:\n  secrets:\n    allowlist: []\n")
+	updated, added, err := AddAllowlistEntry(data, "secrets", AllowlistEntry{Fingerprint: testFingerprint})
+	if err != nil || !added {
+		t.Fatalf("expected entry to be added, got %v, %v", added, err)
+	}
+	config, _, issues := parseConfig(updated)
+	if len(issues) != 0 {
+		t.Fatalf("updated config should be valid, got %v:\n%s", issues, updated)
+	}
+	if len(config.Validators["secrets"].Allowlist) != 1 {
+		t.Fatalf("updated config should parse with the entry, got %+v", config.Validators["secrets"].Allowlist)
+	}
+}
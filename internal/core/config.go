@@ -11,61 +11,207 @@ import (
 
 // Config основная конфигурация хуков
 type Config struct {
+	Version    int                        `yaml:"version"`
 	General    GeneralConfig              `yaml:"general"`
 	Validators map[string]ValidatorConfig `yaml:"validators"`
 	Tools      map[string]ToolConfig      `yaml:"tools"`
 	Logger     LoggerConfig               `yaml:"logger"`
+
+	// deprecations предупреждения об устаревшем формате, примененном при загрузке
+	deprecations []string
+}
+
+// Deprecations возвращает предупреждения о миграциях, выполненных при загрузке
+func (c *Config) Deprecations() []string {
+	return c.deprecations
 }
 
 // GeneralConfig общие настройки
+// Уровень и файл логирования задаются только в секции logger
 type GeneralConfig struct {
-	LogLevel string `yaml:"log_level"`
-	LogFile  string `yaml:"log_file"`
-	Timeout  int    `yaml:"timeout"`
+	Timeout      int                `yaml:"timeout"`
+	Suppressions SuppressionsConfig `yaml:"suppressions"`
+	// Baseline путь к файлу известных нарушений, относительный путь считается от корня проекта
+	Baseline string `yaml:"baseline"`
+	// AuditFile JSONL журнал решений PreToolUse для claude-hooks stats, пустое значение отключает
+	AuditFile string `yaml:"audit_file"`
+}
+
+// SuppressionsConfig настройки inline подавлений нарушений
+type SuppressionsConfig struct {
+	// RequireApproval требует подтверждения пользователя для подавлений, добавленных агентом
+	RequireApproval bool `yaml:"require_approval"`
+}
+
+// Режимы применения правил
+const (
+	// ModeEnforce нарушение влияет на решение хука
+	ModeEnforce = "enforce"
+	// ModeShadow нарушение только записывается в лог и журнал аудита
+	ModeShadow = "shadow"
+	// ModeWarnOnly нарушение понижается до предупреждения и не блокирует вызов
+	ModeWarnOnly = "warn-only"
+)
+
+// Участки исходного кода для поиска совпадений (match_scope)
+const (
+	// ScopeCode исполняемый код вне комментариев и строк
+	ScopeCode = "code"
+	// ScopeString содержимое строковых литералов
+	ScopeString = "string"
+	// ScopeComment комментарии
+	ScopeComment = "comment"
+)
+
+// RuleConfig настройки отдельного правила (типа нарушения)
+type RuleConfig struct {
+	Mode    string `yaml:"mode,omitempty"`
+	Enabled *bool  `yaml:"enabled,omitempty"` // nil - включено
 }
 
 // ValidatorConfig конфигурация валидатора
 type ValidatorConfig struct {
 	Enabled           bool     `yaml:"enabled"`
-	ExceptionPaths    []string `yaml:"exception_paths"`
-	ExceptionFiles    []string `yaml:"exception_files"`
-	CustomPatterns    []string `yaml:"custom_patterns"`
+	ExceptionPaths    []string `yaml:"exception_paths" config:"glob"`
+	ExceptionFiles    []string `yaml:"exception_files" config:"glob"`
+	CustomPatterns    []string `yaml:"custom_patterns" config:"regex"`
 	SuggestionMessage string   `yaml:"suggestion_message"`
 
+	// Встроенные исключения (документация, тесты, фикстуры), nil означает включены
+	BuiltinExemptions *bool `yaml:"builtin_exemptions,omitempty"`
+
+	// Участки кода для поиска совпадений: code, string, comment (по умолчанию зависит от валидатора)
+	MatchScope []string `yaml:"match_scope,omitempty"`
+
+	// Режим применения: для всех правил валидатора и по типу нарушения (по умолчанию enforce)
+	Mode  string                `yaml:"mode,omitempty"`
+	Rules map[string]RuleConfig `yaml:"rules,omitempty"`
+
 	// Специфичные для emergency_defaults validator
 	CaseSensitive bool `yaml:"case_sensitive"`
+	// Настройки языковых наборов по языку: go, js, python, shell
+	Packs map[string]DefaultsPackConfig `yaml:"packs,omitempty"`
 
 	// Специфичные для panic validator
-	GoFilesOnly     bool     `yaml:"go_files_only"`
-	TestExceptions  []string `yaml:"test_exceptions"`
+	GoFilesOnly     bool     `yaml:"go_files_only"`       // устарело, то же что languages: [go]
+	Languages       []string `yaml:"languages,omitempty"` // проверяемые языки, также для emergency_defaults
+	TestExceptions  []string `yaml:"test_exceptions" config:"glob"`
 	ProductionPaths []string `yaml:"production_paths"`
+	// Дополнительные функции завершения в виде "путь/импорта.Имя"
+	FatalFunctions []string `yaml:"fatal_functions,omitempty"`
 
 	// Специфичные для secrets validator
-	JWTPattern           string   `yaml:"jwt_pattern"`
-	WalletPattern        string   `yaml:"wallet_pattern"`
-	TestConfigExceptions []string `yaml:"test_config_exceptions"`
+	JWTPattern           string           `yaml:"jwt_pattern" config:"regex"`
+	WalletPattern        string           `yaml:"wallet_pattern" config:"regex"` // дополнительный шаблон адресов, без проверки контрольной суммы
+	TestConfigExceptions []string         `yaml:"test_config_exceptions" config:"glob"`
+	Entropy              EntropyConfig    `yaml:"entropy,omitempty"`
+	Structured           StructuredConfig `yaml:"structured,omitempty"`
+	Wallets              WalletConfig     `yaml:"wallets,omitempty"`
+	JWT                  JWTConfig        `yaml:"jwt,omitempty"`
+	// Известные безопасные значения по SHA-256 отпечатку
+	Allowlist []AllowlistEntry `yaml:"allowlist,omitempty"`
+}
+
+// EntropyConfig настройки поиска секретов без известного префикса по энтропии Шеннона
+// Нулевые значения означают значения по умолчанию
+type EntropyConfig struct {
+	Enabled         *bool    `yaml:"enabled,omitempty"`          // nil - включено
+	HexThreshold    float64  `yaml:"hex_threshold,omitempty"`    // бит на символ для hex значений
+	Base64Threshold float64  `yaml:"base64_threshold,omitempty"` // бит на символ для остальных значений
+	MinLength       int      `yaml:"min_length,omitempty"`
+	Names           []string `yaml:"names,omitempty" config:"glob"` // шаблоны имен переменных и ключей
+}
+
+// StructuredConfig настройки разбора конфигурационных файлов (JSON, YAML, TOML, .env) по ключам
+type StructuredConfig struct {
+	Enabled *bool    `yaml:"enabled,omitempty"`            // nil - включено
+	Keys    []string `yaml:"keys,omitempty" config:"glob"` // шаблоны чувствительных ключей, заменяют встроенные
+}
+
+// DefaultsPackConfig настройки языкового набора emergency_defaults для команды
+type DefaultsPackConfig struct {
+	// Допустимые конструкции: строки кода с совпадением не проверяются
+	Allowed []string `yaml:"allowed,omitempty" config:"regex"`
+	// Дополнительные запрещенные конструкции, сообщаются как custom_default
+	Patterns []string `yaml:"patterns,omitempty" config:"regex"`
+}
+
+// WalletConfig настройки поиска адресов блокчейн кошельков с проверкой контрольных сумм
+type WalletConfig struct {
+	Chains []string `yaml:"chains,omitempty"` // ethereum, bitcoin, solana, tron; пусто - все, кроме solana
+	// Ethereum адреса без EIP-55 контрольной суммы (в одном регистре) не блокируются
+	// nil - контрольная сумма обязательна, false - адреса в одном регистре тоже блокируются
+	RequireChecksum *bool `yaml:"require_checksum,omitempty"`
+	// Известные публичные адреса (официальные контракты), которые всегда разрешены
+	PublicAddresses []string `yaml:"public_addresses,omitempty"`
+}
+
+// JWTConfig настройки классификации найденных JWT токенов
+type JWTConfig struct {
+	// Издатели (iss) тестовых токенов: такие токены получают тип test_issuer_jwt
+	TestIssuers []string `yaml:"test_issuers,omitempty"`
+}
+
+// RuleMode возвращает режим правила: настройка правила, затем валидатора
+func (c ValidatorConfig) RuleMode(ruleType string) string {
+	return resolveMode(c.Mode, c.Rules, ruleType)
+}
+
+// RuleEnabled проверяет, не отключено ли правило валидатора
+func (c ValidatorConfig) RuleEnabled(ruleType string) bool {
+	return ruleEnabled(c.Rules, ruleType)
 }
 
 // ToolConfig конфигурация инструмента
 type ToolConfig struct {
-	Enabled             bool              `yaml:"enabled"`
-	DangerousCommands   []string          `yaml:"dangerous_commands"`
-	BlockedPatterns     []string          `yaml:"blocked_patterns"`
-	Formatters          map[string]string `yaml:"formatters"`
-	GoFormat            bool              `yaml:"go_format"`
-	TSFormat            bool              `yaml:"ts_format"`
-	KDEOnly             bool              `yaml:"kde_only"`
-	FlashDuration       int               `yaml:"flash_duration"`
-	WorkDir             string            `yaml:"work_dir"`
-	Sound               bool              `yaml:"sound"`
-	Desktop             bool              `yaml:"desktop"`
+	Enabled         bool              `yaml:"enabled"`
+	BlockedPatterns []string          `yaml:"blocked_patterns"`
+	Formatters      map[string]string `yaml:"formatters"`
+	GoFormat        bool              `yaml:"go_format"`
+	TSFormat        bool              `yaml:"ts_format"`
+	KDEOnly         bool              `yaml:"kde_only"`
+	FlashDuration   int               `yaml:"flash_duration"`
+	WorkDir         string            `yaml:"work_dir"`
+	Sound           bool              `yaml:"sound"`
+	Desktop         bool              `yaml:"desktop"`
+
+	// Режим применения нарушений инструмента, как у валидаторов
+	Mode  string                `yaml:"mode,omitempty"`
+	Rules map[string]RuleConfig `yaml:"rules,omitempty"`
+}
+
+// RuleMode возвращает режим правила инструмента
+func (c ToolConfig) RuleMode(ruleType string) string {
+	return resolveMode(c.Mode, c.Rules, ruleType)
+}
+
+// RuleEnabled проверяет, не отключено ли правило инструмента
+func (c ToolConfig) RuleEnabled(ruleType string) bool {
+	return ruleEnabled(c.Rules, ruleType)
+}
+
+// ruleEnabled проверяет настройку enabled правила, по умолчанию правило включено
+func ruleEnabled(rules map[string]RuleConfig, ruleType string) bool {
+	rule, ok := rules[ruleType]
+	return !ok || rule.Enabled == nil || *rule.Enabled
+}
+
+// resolveMode выбирает режим правила с учетом значения по умолчанию
+func resolveMode(mode string, rules map[string]RuleConfig, ruleType string) string {
+	if rule, ok := rules[ruleType]; ok && rule.Mode != "" {
+		return strings.ToLower(rule.Mode)
+	}
+	if mode != "" {
+		return strings.ToLower(mode)
+	}
+	return ModeEnforce
 }
 
 // LoadConfig загружает конфигурацию из файла
 func LoadConfig(configPath string) (*Config, error) {
 	// Если путь не указан, используем значение по умолчанию
 	if configPath == "" {
-		configPath = getDefaultConfigPath()
+		configPath = DefaultConfigPath()
 	}
 
 	// Проверяем существование файла
@@ -84,21 +230,16 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	// Парсим YAML
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	// Строго парсим YAML: неизвестные ключи, типы, regex и семантика
+	config, _, issues := parseConfig(data)
+	if len(issues) > 0 {
+		return nil, &ConfigError{File: configPath, Issues: issues}
 	}
 
 	// Расширяем ~ в путях конфигурации
-	expandConfigPaths(&config)
+	expandConfigPaths(config)
 
-	// Валидируем конфигурацию
-	if err := validateConfig(&config); err != nil {
-		return nil, fmt.Errorf("config validation failed: %w", err)
-	}
-
-	return &config, nil
+	return config, nil
 }
 
 // SaveConfig сохраняет конфигурацию в файл
@@ -128,9 +269,11 @@ func DefaultConfig() *Config {
 	logDir := filepath.Join(homeDir, ".claude", "logs")
 
 	return &Config{
+		Version: CurrentConfigVersion,
 		General: GeneralConfig{
-			LogLevel: "info",
-			LogFile:  filepath.Join(logDir, "claude-hooks.log"),
+			Timeout:   5000,
+			Baseline:  ".claude-hooks-baseline.json",
+			AuditFile: filepath.Join(logDir, "claude-hooks-audit.jsonl"),
 		},
 		Validators: map[string]ValidatorConfig{
 			"emergency_defaults": {
@@ -142,14 +285,12 @@ func DefaultConfig() *Config {
 			},
 			"runtime_exit": {
 				Enabled:         true,
-				GoFilesOnly:     true,
 				TestExceptions:  []string{"*_test.go", "tests/", "test/"},
 				ProductionPaths: []string{"backend/", "src/", "internal/"},
 			},
 			"secrets": {
 				Enabled:              true,
 				JWTPattern:           "eyJ[a-zA-Z0-9+/]+",
-				WalletPattern:        "0x[a-fA-F0-9]{40}",
 				TestConfigExceptions: []string{"test-config.ts", "test-config.js", "*test*.json"},
 			},
 		},
@@ -185,29 +326,91 @@ func DefaultConfig() *Config {
 	}
 }
 
-// validateConfig проверяет корректность конфигурации
-func validateConfig(config *Config) error {
+// validateConfig проверяет семантику конфигурации и возвращает все найденные проблемы
+// document используется для определения позиций проблем и может быть nil
+func validateConfig(config *Config, document *yaml.Node) []ConfigIssue {
+	var issues []ConfigIssue
+	report := func(path, message string) {
+		issue := ConfigIssue{Path: path, Message: message}
+		if node := lookupNode(document, path); node != nil {
+			issue.Line, issue.Column = node.Line, node.Column
+		}
+		issues = append(issues, issue)
+	}
+
 	// Проверяем уровень логирования
-	validLogLevels := []string{"debug", "info", "warn", "warning", "error"}
-	if !contains(validLogLevels, config.General.LogLevel) {
-		return fmt.Errorf("invalid log level: %s", config.General.LogLevel)
+	if !contains(validLogLevels, config.Logger.Level) {
+		report("logger.level", fmt.Sprintf("invalid log level: %q", config.Logger.Level))
 	}
 
 	// Проверяем конфигурацию логгера
-	validOutputs := []string{"stdout", "stderr", "file"}
-	if !contains(validOutputs, config.Logger.Output) {
-		return fmt.Errorf("invalid logger output: %s", config.Logger.Output)
+	if !contains(validLoggerOutputs, config.Logger.Output) {
+		report("logger.output", fmt.Sprintf("invalid logger output: %q", config.Logger.Output))
+	}
+
+	if config.Logger.Format != "" && !contains(validLoggerFormats, config.Logger.Format) {
+		report("logger.format", fmt.Sprintf("invalid logger format: %q", config.Logger.Format))
 	}
 
 	if config.Logger.Output == "file" && config.Logger.LogFile == "" {
-		return fmt.Errorf("logger.file is required when output is 'file'")
+		report("logger.file", "logger.file is required when output is 'file'")
 	}
 
-	return nil
+	// Проверяем режимы правил
+	checkMode := func(path, mode string) {
+		if mode != "" && !contains(validRuleModes, mode) {
+			report(path, fmt.Sprintf("invalid mode: %q (expected %s)", mode, strings.Join(validRuleModes, ", ")))
+		}
+	}
+	for name, validator := range config.Validators {
+		checkMode("validators."+name+".mode", validator.Mode)
+		for _, function := range validator.FatalFunctions {
+			dot := strings.LastIndex(function, ".")
+			if dot <= 0 || dot < strings.LastIndex(function, "/") || dot == len(function)-1 {
+				report("validators."+name+".fatal_functions", fmt.Sprintf("invalid fatal function: %q (expected import/path.Name)", function))
+			}
+		}
+		languages, ok := validValidatorLanguages[name]
+		if !ok {
+			languages = validLanguages
+		}
+		for _, language := range validator.Languages {
+			if !contains(languages, strings.ToLower(language)) {
+				report("validators."+name+".languages", fmt.Sprintf("invalid language: %q (expected %s)", language, strings.Join(languages, ", ")))
+			}
+		}
+		for language := range validator.Packs {
+			if !contains(languages, strings.ToLower(language)) {
+				report("validators."+name+".packs."+language, fmt.Sprintf("invalid language: %q (expected %s)", language, strings.Join(languages, ", ")))
+			}
+		}
+		for _, scope := range validator.MatchScope {
+			if !contains(validMatchScopes, scope) {
+				report("validators."+name+".match_scope", fmt.Sprintf("invalid match scope: %q (expected %s)", scope, strings.Join(validMatchScopes, ", ")))
+			}
+		}
+		for rule, ruleConfig := range validator.Rules {
+			checkMode("validators."+name+".rules."+rule+".mode", ruleConfig.Mode)
+		}
+		for _, chain := range validator.Wallets.Chains {
+			if !contains(validWalletChains, chain) {
+				report("validators."+name+".wallets.chains", fmt.Sprintf("invalid chain: %q (expected %s)", chain, strings.Join(validWalletChains, ", ")))
+			}
+		}
+		validateAllowlist(validator.Allowlist, "validators."+name+".allowlist", report)
+	}
+	for name, tool := range config.Tools {
+		checkMode("tools."+name+".mode", tool.Mode)
+		for rule, ruleConfig := range tool.Rules {
+			checkMode("tools."+name+".rules."+rule+".mode", ruleConfig.Mode)
+		}
+	}
+
+	return issues
 }
 
-// getDefaultConfigPath возвращает путь к конфигурации по умолчанию
-func getDefaultConfigPath() string {
+// DefaultConfigPath возвращает путь к конфигурации по умолчанию
+func DefaultConfigPath() string {
 	homeDir, _ := os.UserHomeDir()
 	return filepath.Join(homeDir, ".claude", "hooks", "config.yaml")
 }
@@ -233,9 +436,7 @@ func expandPath(path string) string {
 
 // expandConfigPaths применяет expandPath к всем путям в конфигурации
 func expandConfigPaths(config *Config) {
-	// Расширяем пути в общих настройках
-	config.General.LogFile = expandPath(config.General.LogFile)
-
 	// Расширяем пути в настройках логгера
 	config.Logger.LogFile = expandPath(config.Logger.LogFile)
+	config.General.AuditFile = expandPath(config.General.AuditFile)
 }
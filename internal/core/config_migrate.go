@@ -0,0 +1,265 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentConfigVersion текущая версия формата конфигурации
+const CurrentConfigVersion = 2
+
+// MigrationChange описывает одно преобразование при миграции конфигурации
+type MigrationChange struct {
+	Line        int    `json:"line,omitempty"`
+	Path        string `json:"path"`
+	Description string `json:"description"`
+}
+
+// String форматирует изменение в виде "line N: path: description"
+func (c MigrationChange) String() string {
+	if c.Line > 0 {
+		return fmt.Sprintf("line %d: %s: %s", c.Line, c.Path, c.Description)
+	}
+	return fmt.Sprintf("%s: %s", c.Path, c.Description)
+}
+
+// configMigration преобразование документа между версиями формата
+type configMigration struct {
+	from  int
+	apply func(document *yaml.Node) []MigrationChange
+}
+
+// configMigrations цепочка миграций, применяемых по порядку
+var configMigrations = []configMigration{
+	{from: 1, apply: migrateV1ToV2},
+}
+
+// MigrateConfigData переводит YAML конфигурацию в текущий формат с сохранением комментариев
+func MigrateConfigData(data []byte) ([]byte, []MigrationChange, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if root.Kind == 0 || len(root.Content) == 0 {
+		return data, nil, nil
+	}
+
+	changes, err := migrateDocument(root.Content[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(changes) == 0 {
+		return data, nil, nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&root); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode migrated config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode migrated config: %w", err)
+	}
+
+	return buf.Bytes(), changes, nil
+}
+
+// migrateDocument применяет к документу все миграции начиная с его версии
+func migrateDocument(document *yaml.Node) ([]MigrationChange, error) {
+	if document.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	version, err := configVersion(document)
+	if err != nil {
+		return nil, err
+	}
+	if version > CurrentConfigVersion {
+		return nil, fmt.Errorf("unsupported config version %d (this build supports up to %d)", version, CurrentConfigVersion)
+	}
+	if version == CurrentConfigVersion {
+		return nil, nil
+	}
+
+	var changes []MigrationChange
+	for _, migration := range configMigrations {
+		if migration.from >= version {
+			changes = append(changes, migration.apply(document)...)
+		}
+	}
+
+	setConfigVersion(document, CurrentConfigVersion)
+	changes = append(changes, MigrationChange{
+		Path:        "version",
+		Description: fmt.Sprintf("set format version %d (was %d)", CurrentConfigVersion, version),
+	})
+
+	return changes, nil
+}
+
+// configVersion читает версию формата, отсутствие ключа означает версию 1
+func configVersion(document *yaml.Node) (int, error) {
+	_, value := mappingEntry(document, "version")
+	if value == nil {
+		return 1, nil
+	}
+	version, err := strconv.Atoi(value.Value)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("line %d: invalid config version %q", value.Line, value.Value)
+	}
+	return version, nil
+}
+
+// setConfigVersion записывает версию формата первым ключом документа
+func setConfigVersion(document *yaml.Node, version int) {
+	value := strconv.Itoa(version)
+	if _, existing := mappingEntry(document, "version"); existing != nil {
+		existing.Value = value
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+	val := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: value}
+	document.Content = append([]*yaml.Node{key, val}, document.Content...)
+
+	// Комментарий заголовка файла остается над первым ключом
+	if len(document.Content) > 2 {
+		key.HeadComment = document.Content[2].HeadComment
+		document.Content[2].HeadComment = ""
+	}
+}
+
+// migrateV1ToV2 убирает дублирующиеся и устаревшие ключи формата версии 1
+func migrateV1ToV2(document *yaml.Node) []MigrationChange {
+	var changes []MigrationChange
+
+	// general.log_file и general.log_level дублируют настройки logger
+	if _, general := mappingEntry(document, "general"); general != nil && general.Kind == yaml.MappingNode {
+		logger := ensureMapping(document, "logger")
+		changes = append(changes, moveScalar(general, "log_file", logger, "file", "general.log_file", "logger.file")...)
+		changes = append(changes, moveScalar(general, "log_level", logger, "level", "general.log_level", "logger.level")...)
+		if len(general.Content) == 0 {
+			removeMappingEntry(document, "general")
+		}
+	}
+
+	// validators.*.exceptions никогда не применялся - правильный ключ exception_paths
+	if _, validators := mappingEntry(document, "validators"); validators != nil && validators.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(validators.Content); i += 2 {
+			name, validator := validators.Content[i].Value, validators.Content[i+1]
+			if validator.Kind != yaml.MappingNode {
+				continue
+			}
+			path := "validators." + name
+			changes = append(changes, mergeList(validator, "exceptions", "exception_paths", path)...)
+		}
+	}
+
+	// tools.bash.dangerous_commands заменен на blocked_patterns
+	if _, tools := mappingEntry(document, "tools"); tools != nil && tools.Kind == yaml.MappingNode {
+		if _, bash := mappingEntry(tools, "bash"); bash != nil && bash.Kind == yaml.MappingNode {
+			changes = append(changes, mergeList(bash, "dangerous_commands", "blocked_patterns", "tools.bash")...)
+		}
+	}
+
+	return changes
+}
+
+// moveScalar переносит скалярное значение между секциями, не перезаписывая существующее
+func moveScalar(from *yaml.Node, fromKey string, to *yaml.Node, toKey, fromPath, toPath string) []MigrationChange {
+	key, value := mappingEntry(from, fromKey)
+	if value == nil {
+		return nil
+	}
+	removeMappingEntry(from, fromKey)
+
+	if _, existing := mappingEntry(to, toKey); existing != nil {
+		description := fmt.Sprintf("removed duplicate of %s", toPath)
+		if existing.Value != value.Value {
+			description = fmt.Sprintf("removed %q, %s keeps %q", value.Value, toPath, existing.Value)
+		}
+		return []MigrationChange{{Line: key.Line, Path: fromPath, Description: description}}
+	}
+
+	key.Value = toKey
+	to.Content = append(to.Content, key, value)
+	return []MigrationChange{{Line: key.Line, Path: fromPath, Description: "moved to " + toPath}}
+}
+
+// mergeList переименовывает ключ списка, объединяя значения с уже существующим списком
+func mergeList(mapping *yaml.Node, fromKey, toKey, path string) []MigrationChange {
+	key, value := mappingEntry(mapping, fromKey)
+	if value == nil {
+		return nil
+	}
+
+	fromPath, toPath := path+"."+fromKey, path+"."+toKey
+	_, existing := mappingEntry(mapping, toKey)
+	if existing == nil || existing.Kind != yaml.SequenceNode || value.Kind != yaml.SequenceNode {
+		if existing != nil {
+			removeMappingEntry(mapping, toKey)
+		}
+		key.Value = toKey
+		return []MigrationChange{{Line: key.Line, Path: fromPath, Description: "renamed to " + toPath}}
+	}
+
+	seen := make(map[string]bool, len(existing.Content))
+	for _, item := range existing.Content {
+		seen[item.Value] = true
+	}
+	added := 0
+	for _, item := range value.Content {
+		if !seen[item.Value] {
+			existing.Content = append(existing.Content, item)
+			seen[item.Value] = true
+			added++
+		}
+	}
+	removeMappingEntry(mapping, fromKey)
+
+	return []MigrationChange{{
+		Line:        key.Line,
+		Path:        fromPath,
+		Description: fmt.Sprintf("merged into %s (%d new entries)", toPath, added),
+	}}
+}
+
+// mappingEntry возвращает ключ и значение из YAML mapping
+func mappingEntry(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// removeMappingEntry удаляет ключ из YAML mapping
+func removeMappingEntry(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// ensureMapping возвращает вложенный mapping, создавая его при отсутствии
+func ensureMapping(mapping *yaml.Node, key string) *yaml.Node {
+	if _, value := mappingEntry(mapping, key); value != nil && value.Kind == yaml.MappingNode {
+		return value
+	}
+	removeMappingEntry(mapping, key)
+	value := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	mapping.Content = append(mapping.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		value,
+	)
+	return value
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+const legacyConfigYAML = `# Claude Hooks Configuration
+general:
+  log_level: "debug"
+  log_file: "~/.claude/logs/claude-hooks.log"
+  timeout: 5000
+
+logger:
+  output: "file"
+  file: "~/.claude/logs/claude-hooks.log"
+
+validators:
+  secrets:
+    enabled: true
+    exceptions:
+      - "*_test.go" # fixtures
+    exception_paths:
+      - "docs/"
+
+tools:
+  bash:
+    enabled: true
+    dangerous_commands:
+      - "rm -rf /"
+`
+
+func TestMigrateConfigData_UpgradesLegacyKeys(t *testing.T) {
+	migrated, changes, err := MigrateConfigData([]byte(legacyConfigYAML))
+	if err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+
+	if len(changes) != 5 {
+		t.Fatalf("expected 5 changes, got %d: %v", len(changes), changes)
+	}
+
+	if issues := CheckConfig(migrated); len(issues) > 0 {
+		t.Fatalf("migrated config has issues: %v\n%s", issues, migrated)
+	}
+
+	config, _, issues := parseConfig(migrated)
+	if len(issues) > 0 {
+		t.Fatalf("failed to parse migrated config: %v", issues)
+	}
+	if config.Version != CurrentConfigVersion {
+		t.Errorf("expected version %d, got %d", CurrentConfigVersion, config.Version)
+	}
+	if config.Logger.Level != "debug" {
+		t.Errorf("expected log level to move to logger.level, got %q", config.Logger.Level)
+	}
+	if got := config.Validators["secrets"].ExceptionPaths; len(got) != 2 {
+		t.Errorf("expected merged exception paths, got %v", got)
+	}
+	if got := config.Tools["bash"].BlockedPatterns; len(got) != 1 || got[0] != "rm -rf /" {
+		t.Errorf("expected dangerous_commands in blocked_patterns, got %v", got)
+	}
+	if len(config.Deprecations()) != 0 {
+		t.Errorf("migrated config should not report deprecations, got %v", config.Deprecations())
+	}
+
+	text := string(migrated)
+	for _, comment := range []string{"# Claude Hooks Configuration", "# fixtures"} {
+		if !strings.Contains(text, comment) {
+			t.Errorf("comment %q lost during migration:\n%s", comment, text)
+		}
+	}
+}
+
+func TestMigrateConfigData_CurrentVersionUnchanged(t *testing.T) {
+	data := []byte("version: 2\nlogger:\n  level: info\n  output: stderr\n")
+
+	migrated, changes, err := MigrateConfigData(data)
+	if err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+	if string(migrated) != string(data) {
+		t.Errorf("current config should be returned unchanged")
+	}
+}
+
+func TestParseConfig_LegacyFileLoadsWithDeprecations(t *testing.T) {
+	config, changes, issues := parseConfig([]byte(legacyConfigYAML))
+	if len(issues) > 0 {
+		t.Fatalf("legacy config should load, got issues: %v", issues)
+	}
+	if len(changes) == 0 || len(config.Deprecations()) != len(changes) {
+		t.Errorf("expected deprecation warnings for every change, got %v", config.Deprecations())
+	}
+}
+
+func TestMigrateConfigData_RejectsFutureVersion(t *testing.T) {
+	if _, _, err := MigrateConfigData([]byte("version: 99\n")); err == nil {
+		t.Error("expected error for unsupported version")
+	}
+}
@@ -0,0 +1,465 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigIssue описывает одну проблему конфигурации с позицией в YAML файле
+type ConfigIssue struct {
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// String форматирует проблему в виде "line:column: path: message"
+func (i ConfigIssue) String() string {
+	var b strings.Builder
+	if i.Line > 0 {
+		fmt.Fprintf(&b, "%d:%d: ", i.Line, i.Column)
+	}
+	if i.Path != "" {
+		b.WriteString(i.Path)
+		b.WriteString(": ")
+	}
+	b.WriteString(i.Message)
+	return b.String()
+}
+
+// ConfigError ошибка загрузки конфигурации, содержащая все найденные проблемы
+type ConfigError struct {
+	File   string
+	Issues []ConfigIssue
+}
+
+// Error возвращает все проблемы одной строкой
+func (e *ConfigError) Error() string {
+	parts := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		parts = append(parts, issue.String())
+	}
+	return fmt.Sprintf("%s: %s", e.File, strings.Join(parts, "; "))
+}
+
+// Допустимые значения перечислимых полей конфигурации
+var (
+	validLogLevels     = []string{"debug", "info", "warn", "warning", "error"}
+	validLoggerOutputs = []string{"stdout", "stderr", "file"}
+	validLoggerFormats = []string{"text", "json"}
+	validRuleModes     = []string{ModeEnforce, ModeShadow, ModeWarnOnly}
+	validMatchScopes   = []string{ScopeCode, ScopeString, ScopeComment}
+	validLanguages     = []string{"go", "js", "python", "shell"}
+	validWalletChains  = []string{"ethereum", "bitcoin", "solana", "tron"}
+)
+
+// validValidatorLanguages языки, для которых у валидатора есть набор правил
+// Остальные валидаторы проверяются по общему списку validLanguages
+var validValidatorLanguages = map[string][]string{
+	"runtime_exit":       {"go", "js", "python"},
+	"emergency_defaults": {"go", "js", "python", "shell"},
+}
+
+// schemaEnums перечисления для JSON Schema по пути поля ("*" - любой ключ map)
+var schemaEnums = map[string][]string{
+	"logger.level":  validLogLevels,
+	"logger.output": validLoggerOutputs,
+	"logger.format": validLoggerFormats,
+
+	"validators.*.mode":           validRuleModes,
+	"validators.*.match_scope":    validMatchScopes,
+	"validators.*.languages":      validLanguages,
+	"validators.*.rules.*.mode":   validRuleModes,
+	"validators.*.wallets.chains": validWalletChains,
+	"tools.*.mode":                validRuleModes,
+	"tools.*.rules.*.mode":        validRuleModes,
+}
+
+// yamlLinePattern извлекает номер строки из сообщений yaml.v3
+var yamlLinePattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// CheckConfigFile проверяет конфигурационный файл и возвращает все найденные проблемы
+// и миграции устаревшего формата, которые будут применены при загрузке
+func CheckConfigFile(configPath string) ([]ConfigIssue, []MigrationChange, error) {
+	if configPath == "" {
+		configPath = DefaultConfigPath()
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	_, changes, issues := parseConfig(data)
+	return issues, changes, nil
+}
+
+// CheckConfig проверяет YAML конфигурацию и возвращает все найденные проблемы
+func CheckConfig(data []byte) []ConfigIssue {
+	_, _, issues := parseConfig(data)
+	return issues
+}
+
+// parseConfig строго разбирает конфигурацию: неизвестные ключи, типы, regex и семантика
+// Устаревший формат мигрируется в памяти, изменения возвращаются для предупреждений
+func parseConfig(data []byte) (*Config, []MigrationChange, []ConfigIssue) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, nil, yamlErrorIssues(err)
+	}
+
+	// Пустой файл - пустая конфигурация
+	if root.Kind == 0 || len(root.Content) == 0 {
+		var config Config
+		return &config, nil, validateConfig(&config, nil)
+	}
+
+	document := root.Content[0]
+	changes, err := migrateDocument(document)
+	if err != nil {
+		return nil, nil, []ConfigIssue{{Path: "version", Message: err.Error()}}
+	}
+
+	var issues []ConfigIssue
+	checkNode(document, reflect.TypeOf(Config{}), "", &issues)
+
+	// При ошибках типов yaml.v3 декодирует частично - семантику проверяем все равно
+	var config Config
+	if err := document.Decode(&config); err != nil && len(issues) == 0 {
+		issues = append(issues, yamlErrorIssues(err)...)
+	}
+
+	// Семантические проблемы по полям со структурными ошибками не дублируем
+	reported := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		reported[issue.Path] = true
+	}
+	for _, issue := range validateConfig(&config, document) {
+		if !reported[issue.Path] {
+			issues = append(issues, issue)
+		}
+	}
+
+	if len(issues) > 0 {
+		return nil, changes, issues
+	}
+
+	for _, change := range changes {
+		config.deprecations = append(config.deprecations, change.String())
+	}
+
+	return &config, changes, nil
+}
+
+// yamlErrorIssues превращает ошибку yaml.v3 в список проблем с номерами строк
+func yamlErrorIssues(err error) []ConfigIssue {
+	var messages []string
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		messages = typeErr.Errors
+	} else {
+		messages = []string{err.Error()}
+	}
+
+	issues := make([]ConfigIssue, 0, len(messages))
+	for _, message := range messages {
+		issue := ConfigIssue{Message: message}
+		if m := yamlLinePattern.FindStringSubmatch(message); m != nil {
+			issue.Line, _ = strconv.Atoi(m[1])
+			issue.Column = 1
+			issue.Message = m[2]
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// checkNode рекурсивно сверяет YAML узел с Go типом конфигурации
+func checkNode(node *yaml.Node, t reflect.Type, path string, issues *[]ConfigIssue) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// null допустим для любого поля
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!null" {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if !expectKind(node, yaml.MappingNode, "mapping", path, issues) {
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			field, ok := fields[key.Value]
+			if !ok {
+				*issues = append(*issues, unknownKeyIssue(key, path, fields))
+				continue
+			}
+			fieldPath := joinConfigPath(path, key.Value)
+			checkNode(value, field.Type, fieldPath, issues)
+			switch field.Tag.Get("config") {
+			case "regex":
+				checkRegexNode(value, fieldPath, issues)
+			case "glob":
+				checkGlobNode(value, fieldPath, issues)
+			}
+		}
+
+	case reflect.Map:
+		if !expectKind(node, yaml.MappingNode, "mapping", path, issues) {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			checkNode(node.Content[i+1], t.Elem(), joinConfigPath(path, node.Content[i].Value), issues)
+		}
+
+	case reflect.Slice:
+		if !expectKind(node, yaml.SequenceNode, "list", path, issues) {
+			return
+		}
+		for i, item := range node.Content {
+			checkNode(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), issues)
+		}
+
+	case reflect.Bool:
+		if expectKind(node, yaml.ScalarNode, "boolean", path, issues) && node.ShortTag() != "!!bool" {
+			*issues = append(*issues, issueAt(node, path, fmt.Sprintf("expected boolean, got %q", node.Value)))
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if expectKind(node, yaml.ScalarNode, "integer", path, issues) && node.ShortTag() != "!!int" {
+			*issues = append(*issues, issueAt(node, path, fmt.Sprintf("expected integer, got %q", node.Value)))
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if expectKind(node, yaml.ScalarNode, "number", path, issues) && node.ShortTag() != "!!float" && node.ShortTag() != "!!int" {
+			*issues = append(*issues, issueAt(node, path, fmt.Sprintf("expected number, got %q", node.Value)))
+		}
+
+	case reflect.String:
+		expectKind(node, yaml.ScalarNode, "string", path, issues)
+	}
+}
+
+// expectKind проверяет вид YAML узла и регистрирует проблему при несовпадении
+func expectKind(node *yaml.Node, kind yaml.Kind, name, path string, issues *[]ConfigIssue) bool {
+	if node.Kind == kind {
+		return true
+	}
+	*issues = append(*issues, issueAt(node, path, fmt.Sprintf("expected %s, got %s", name, nodeKindName(node))))
+	return false
+}
+
+// checkGlobNode проверяет синтаксис шаблонов путей в стиле .gitignore
+func checkGlobNode(node *yaml.Node, path string, issues *[]ConfigIssue) {
+	if node.Kind != yaml.SequenceNode {
+		return
+	}
+	for _, value := range node.Content {
+		if value.Kind != yaml.ScalarNode {
+			continue
+		}
+		glob := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(value.Value), "!"), "/")
+		if !doublestar.ValidatePattern(glob) {
+			*issues = append(*issues, issueAt(value, path, fmt.Sprintf("invalid glob pattern: %q", value.Value)))
+		}
+	}
+}
+
+// checkRegexNode компилирует regex значения поля (строку или список строк)
+func checkRegexNode(node *yaml.Node, path string, issues *[]ConfigIssue) {
+	values := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		values = node.Content
+	}
+	for _, value := range values {
+		if value.Kind != yaml.ScalarNode || value.Value == "" {
+			continue
+		}
+		if _, err := regexp.Compile(value.Value); err != nil {
+			*issues = append(*issues, issueAt(value, path, fmt.Sprintf("invalid regular expression: %v", err)))
+		}
+	}
+}
+
+// unknownKeyIssue создает проблему неизвестного ключа с подсказкой ближайшего известного
+func unknownKeyIssue(key *yaml.Node, path string, fields map[string]reflect.StructField) ConfigIssue {
+	message := fmt.Sprintf("unknown key %q", key.Value)
+	if suggestion := closestKey(key.Value, fields); suggestion != "" {
+		message += fmt.Sprintf(" (did you mean %q?)", suggestion)
+	}
+	return issueAt(key, path, message)
+}
+
+// closestKey находит известный ключ с минимальным расстоянием редактирования
+func closestKey(unknown string, fields map[string]reflect.StructField) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	best, bestDistance := "", 3
+	for _, name := range names {
+		if d := levenshtein(unknown, name); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// levenshtein вычисляет расстояние редактирования между строками
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(b)]
+}
+
+// yamlFields возвращает поля структуры по их YAML именам
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// issueAt создает проблему с позицией YAML узла
+func issueAt(node *yaml.Node, path, message string) ConfigIssue {
+	return ConfigIssue{Line: node.Line, Column: node.Column, Path: path, Message: message}
+}
+
+// nodeKindName возвращает читаемое имя вида YAML узла
+func nodeKindName(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "mapping"
+	case yaml.SequenceNode:
+		return "list"
+	case yaml.ScalarNode:
+		return fmt.Sprintf("scalar %q", node.Value)
+	default:
+		return "unsupported node"
+	}
+}
+
+// joinConfigPath соединяет сегменты пути к полю конфигурации
+func joinConfigPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// lookupNode находит YAML узел значения по пути вида "logger.output"
+func lookupNode(document *yaml.Node, path string) *yaml.Node {
+	node := document
+	for _, key := range strings.Split(path, ".") {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				next = node.Content[i+1]
+				break
+			}
+		}
+		node = next
+	}
+	return node
+}
+
+// ConfigSchema генерирует JSON Schema конфигурации из Go типов
+func ConfigSchema() map[string]any {
+	schema := schemaFor(reflect.TypeOf(Config{}), reflect.StructField{}, "")
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["$id"] = "https://github.com/aiseeq/claude-hooks/configs/hooks.schema.json"
+	schema["title"] = "claude-hooks configuration"
+	return schema
+}
+
+// schemaFor строит JSON Schema для Go типа
+func schemaFor(t reflect.Type, field reflect.StructField, path string) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		properties := make(map[string]any)
+		for name, f := range yamlFields(t) {
+			properties[name] = schemaFor(f.Type, f, joinConfigPath(path, name))
+		}
+		return map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+	case reflect.Map:
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": schemaFor(t.Elem(), field, joinConfigPath(path, "*")),
+		}
+	case reflect.Slice:
+		return map[string]any{
+			"type":  "array",
+			"items": schemaFor(t.Elem(), field, path),
+		}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	default:
+		schema := map[string]any{"type": "string"}
+		if field.Tag.Get("config") == "regex" {
+			schema["format"] = "regex"
+		}
+		if enum, ok := schemaEnums[path]; ok {
+			schema["enum"] = enum
+		}
+		return schema
+	}
+}
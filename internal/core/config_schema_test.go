@@ -0,0 +1,295 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+const validConfigYAML = `version: 2
+logger:
+  level: "info"
+  output: "stderr"
+validators:
+  secrets:
+    enabled: true
+    exception_paths: ["docs/"]
+`
+
+func TestCheckConfig_ValidConfig(t *testing.T) {
+	if issues := CheckConfig([]byte(validConfigYAML)); len(issues) > 0 {
+		t.Fatalf("expected no issues, got %v", issues)
+	}
+}
+
+func TestCheckConfig_UnknownKeysWithPositions(t *testing.T) {
+	data := `version: 2
+logger:
+  level: "info"
+  output: "stderr"
+validators:
+  secrets:
+    enabled: true
+    exceptions:
+      - "*_test.go"
+  runtime_exit:
+    enabeld: true
+`
+
+	issues := CheckConfig([]byte(data))
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d: %v", len(issues), issues)
+	}
+
+	if issues[0].Line != 8 || issues[0].Column != 5 || !strings.Contains(issues[0].Message, `"exceptions"`) {
+		t.Errorf("unexpected first issue: %+v", issues[0])
+	}
+	if issues[1].Line != 11 || !strings.Contains(issues[1].Message, `did you mean "enabled"`) {
+		t.Errorf("unexpected second issue: %+v", issues[1])
+	}
+}
+
+func TestCheckConfig_ReportsEveryProblem(t *testing.T) {
+	data := `version: 2
+general:
+  timeout: fast
+logger:
+  level: "loud"
+  output: "stderr"
+validators:
+  secrets:
+    enabled: yes
+    jwt_pattern: "eyJ[("
+    custom_patterns: ["ok", "(bad"]
+`
+
+	issues := CheckConfig([]byte(data))
+
+	wantPaths := []string{
+		"general.timeout",
+		"validators.secrets.enabled",
+		"validators.secrets.jwt_pattern",
+		"validators.secrets.custom_patterns",
+		"logger.level",
+	}
+	if len(issues) != len(wantPaths) {
+		t.Fatalf("expected %d issues, got %d: %v", len(wantPaths), len(issues), issues)
+	}
+	for i, path := range wantPaths {
+		if issues[i].Path != path {
+			t.Errorf("issue %d: expected path %s, got %s", i, path, issues[i].Path)
+		}
+		if issues[i].Line == 0 {
+			t.Errorf("issue %d: expected line number, got %+v", i, issues[i])
+		}
+	}
+}
+
+func TestConfigSchema_DisallowsUnknownKeys(t *testing.T) {
+	schema := ConfigSchema()
+
+	if schema["additionalProperties"] != false {
+		t.Error("root schema should disallow additional properties")
+	}
+
+	properties := schema["properties"].(map[string]any)
+	validators := properties["validators"].(map[string]any)
+	validator := validators["additionalProperties"].(map[string]any)
+	fields := validator["properties"].(map[string]any)
+
+	jwt := fields["jwt_pattern"].(map[string]any)
+	if jwt["format"] != "regex" {
+		t.Errorf("jwt_pattern should have regex format, got %v", jwt["format"])
+	}
+}
+
+func TestCheckConfig_RuleModes(t *testing.T) {
+	data := `version: 2
+logger:
+  level: "info"
+  output: "stderr"
+validators:
+  secrets:
+    enabled: true
+    mode: shadow
+    rules:
+      hardcoded_jwt:
+        mode: enforce
+      wallet_address:
+        mode: quiet
+      slack_token:
+        enabled: false
+`
+
+	issues := CheckConfig([]byte(data))
+	if len(issues) != 1 || issues[0].Path != "validators.secrets.rules.wallet_address.mode" || issues[0].Line != 13 {
+		t.Fatalf("expected one invalid mode issue, got %v", issues)
+	}
+
+	config := ValidatorConfig{Mode: ModeShadow, Rules: map[string]RuleConfig{"hardcoded_jwt": {Mode: ModeEnforce}}}
+	if mode := config.RuleMode("hardcoded_jwt"); mode != ModeEnforce {
+		t.Errorf("rule mode should override validator mode, got %s", mode)
+	}
+	if mode := config.RuleMode("wallet_address"); mode != ModeShadow {
+		t.Errorf("validator mode should apply to other rules, got %s", mode)
+	}
+	if mode := (ValidatorConfig{}).RuleMode("any"); mode != ModeEnforce {
+		t.Errorf("default mode should be enforce, got %s", mode)
+	}
+
+	disabled := false
+	config.Rules["slack_token"] = RuleConfig{Enabled: &disabled}
+	if config.RuleEnabled("slack_token") {
+		t.Error("rule with enabled: false should be disabled")
+	}
+	if !config.RuleEnabled("hardcoded_jwt") || !config.RuleEnabled("any") {
+		t.Error("rules should be enabled by default")
+	}
+}
+
+func TestCheckConfig_MatchScope(t *testing.T) {
+	data := `version: 2
+logger:
+  level: "info"
+  output: "stderr"
+validators:
+  secrets:
+    enabled: true
+    match_scope: [string, comment]
+  runtime_exit:
+    enabled: true
+    match_scope: [code, strings]
+`
+
+	issues := CheckConfig([]byte(data))
+	if len(issues) != 1 || issues[0].Path != "validators.runtime_exit.match_scope" || issues[0].Line != 11 {
+		t.Fatalf("expected one invalid match scope issue, got %v", issues)
+	}
+}
+
+func TestCheckConfig_FatalFunctions(t *testing.T) {
+	data := `version: 2
+logger:
+  level: "info"
+  output: "stderr"
+validators:
+  runtime_exit:
+    enabled: true
+    fatal_functions:
+      - "github.com/acme/app/internal/die.Now"
+      - "Exit"
+`
+
+	issues := CheckConfig([]byte(data))
+	if len(issues) != 1 || issues[0].Path != "validators.runtime_exit.fatal_functions" {
+		t.Fatalf("expected one invalid fatal function issue, got %v", issues)
+	}
+}
+
+func TestCheckConfig_WalletChains(t *testing.T) {
+	data := `version: 2
+logger:
+  level: "info"
+  output: "stderr"
+validators:
+  secrets:
+    enabled: true
+    wallets:
+      chains: [ethereum, dogecoin]
+      require_checksum: true
+      public_addresses: ["0xdAC17F958D2ee523a2206206994597C13D831ec7"]
+`
+
+	issues := CheckConfig([]byte(data))
+	if len(issues) != 1 || issues[0].Path != "validators.secrets.wallets.chains" || !strings.Contains(issues[0].Message, "dogecoin") {
+		t.Fatalf("expected one invalid chain issue, got %v", issues)
+	}
+}
+
+func TestCheckConfig_DefaultsPacks(t *testing.T) {
+	data := `version: 2
+logger:
+  level: "info"
+  output: "stderr"
+validators:
+  emergency_defaults:
+    enabled: true
+    languages: [go, python]
+    packs:
+      python:
+        allowed: ['\bsettings\.get\(']
+        patterns: ['(']
+      ruby:
+        patterns: ['\|\|=']
+`
+
+	issues := CheckConfig([]byte(data))
+	paths := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		paths = append(paths, issue.Path)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected invalid pattern and language issues, got %v", issues)
+	}
+	joined := strings.Join(paths, " ")
+	if !strings.Contains(joined, "validators.emergency_defaults.packs.python.patterns") ||
+		!strings.Contains(joined, "validators.emergency_defaults.packs.ruby") {
+		t.Fatalf("unexpected issue paths: %v", paths)
+	}
+}
+
+func TestCheckConfig_ValidatorLanguages(t *testing.T) {
+	data := `version: 2
+logger:
+  level: "info"
+  output: "stderr"
+validators:
+  runtime_exit:
+    enabled: true
+    languages: [go, shell]
+  emergency_defaults:
+    enabled: true
+    languages: [go, shell]
+`
+
+	issues := CheckConfig([]byte(data))
+	if len(issues) != 1 || issues[0].Path != "validators.runtime_exit.languages" || !strings.Contains(issues[0].Message, "shell") {
+		t.Fatalf("expected one invalid runtime_exit language issue, got %v", issues)
+	}
+}
+
+func TestCheckConfig_EntropyThresholds(t *testing.T) {
+	data := `version: 2
+logger:
+  level: "info"
+  output: "stderr"
+validators:
+  secrets:
+    enabled: true
+    entropy:
+      hex_threshold: 3
+      base64_threshold: high
+      min_length: 20
+`
+
+	issues := CheckConfig([]byte(data))
+	if len(issues) != 1 || issues[0].Path != "validators.secrets.entropy.base64_threshold" {
+		t.Fatalf("expected one invalid threshold issue, got %v", issues)
+	}
+}
+
+func TestCheckConfig_InvalidGlob(t *testing.T) {
+	data := `version: 2
+logger:
+  level: "info"
+  output: "stderr"
+validators:
+  secrets:
+    enabled: true
+    exception_files: ["*.gen.go", "!fixtures/[a-"]
+`
+
+	issues := CheckConfig([]byte(data))
+	if len(issues) != 1 || issues[0].Path != "validators.secrets.exception_files" || !strings.Contains(issues[0].Message, "invalid glob") {
+		t.Fatalf("expected one invalid glob issue, got %v", issues)
+	}
+}
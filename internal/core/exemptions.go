@@ -0,0 +1,57 @@
+package core
+
+// Встроенные исключения валидаторов в формате шаблонов exception_paths
+// Отключаются для валидатора настройкой builtin_exemptions: false
+var (
+	// DocumentationExemptions файлы и директории документации
+	DocumentationExemptions = []string{
+		"*.md", "*.txt", "*.rst", "*.adoc",
+		"README", "README.*", "CHANGELOG", "CHANGELOG.*", "LICENSE", "LICENSE.*",
+		"AUTHORS", "AUTHORS.*", "CONTRIBUTORS", "CONTRIBUTORS.*",
+		"docs/", "doc/", "documentation/",
+	}
+
+	// TestExemptions тестовые файлы и директории
+	TestExemptions = []string{
+		"*_test.go", "test/", "tests/", "testing/",
+		"*.test.ts", "*.test.js", "*.test.tsx", "*.test.jsx",
+		"*.spec.ts", "*.spec.js", "*.spec.tsx", "*.spec.jsx",
+		"__tests__/", "test_*.py", "*_test.py", "conftest.py",
+	}
+
+	// validatorExemptions дополнительные исключения конкретных валидаторов
+	validatorExemptions = map[string][]string{
+		// Тестовые конфигурации, фикстуры и шаблоны
+		"emergency_defaults": {
+			"test-config.*", "fixture*", "mock*", "stub*",
+			"*.example", "*.sample", "*.template",
+		},
+		// Примеры и бенчмарки могут использовать критические выходы
+		// package main и init() исключаются по синтаксическому дереву
+		"runtime_exit": {
+			"examples/", "demo/",
+			"benchmark/", "*_bench.go",
+		},
+		// Примеры, шаблоны и тестовые данные
+		"secrets": {
+			"example*", "sample*", "template*", "demo*",
+			"*.example", "*.sample", "*.template",
+			"fixtures/", "mocks/", "stubs/",
+		},
+	}
+)
+
+// BuiltinExemptions возвращает встроенные шаблоны исключений валидатора:
+// документация, тесты и собственные исключения валидатора
+func BuiltinExemptions(validator string) []string {
+	specific := validatorExemptions[validator]
+	patterns := make([]string, 0, len(DocumentationExemptions)+len(TestExemptions)+len(specific))
+	patterns = append(patterns, DocumentationExemptions...)
+	patterns = append(patterns, TestExemptions...)
+	return append(patterns, specific...)
+}
+
+// UseBuiltinExemptions сообщает, применяются ли встроенные исключения (по умолчанию да)
+func (c ValidatorConfig) UseBuiltinExemptions() bool {
+	return c.BuiltinExemptions == nil || *c.BuiltinExemptions
+}
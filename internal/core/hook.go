@@ -14,8 +14,24 @@ const (
 	HookActionAllow HookAction = "allow"
 	HookActionBlock HookAction = "block"
 	HookActionWarn  HookAction = "warn"
+	// HookActionAsk передает решение пользователю через permissionDecision "ask"
+	HookActionAsk HookAction = "ask"
+	// HookActionNotice предупреждение правил в режиме warn-only: сообщение выводится, вызов не блокируется
+	HookActionNotice HookAction = "notice"
 )
 
+// ExitCode возвращает код завершения хука для действия
+// Claude Code считает exit 2 блокировкой: warn останавливает вызов для видимости в интерфейсе,
+// notice (warn-only) и ask - нет, решение ask передается через JSON в stdout
+func (a HookAction) ExitCode() int {
+	switch a {
+	case HookActionBlock, HookActionWarn:
+		return 2
+	default:
+		return 0
+	}
+}
+
 // Level определяет уровень важности сообщения
 type Level string
 
@@ -34,6 +50,7 @@ type ToolInput struct {
 	FilePath       string          `json:"file_path,omitempty"`
 	Content        string          `json:"content,omitempty"`
 	NewString      string          `json:"new_string,omitempty"`
+	OldString      string          `json:"old_string,omitempty"`
 	Command        string          `json:"command,omitempty"`
 	CWD            string          `json:"cwd,omitempty"`
 	TranscriptPath string          `json:"transcript_path,omitempty"`
@@ -42,6 +59,7 @@ type ToolInput struct {
 // FileAnalysis содержит анализируемую информацию о файле
 type FileAnalysis struct {
 	Path       string
+	RelPath    string // путь от корня проекта для шаблонов исключений, см. SetProjectRoot
 	Content    string
 	Extension  string
 	IsTestFile bool
@@ -56,6 +74,11 @@ type Violation struct {
 	Line       int    `json:"line,omitempty"`
 	Column     int    `json:"column,omitempty"`
 	Severity   Level  `json:"severity"`
+	Validator  string `json:"validator,omitempty"` // заполняется движком
+	Shadow     bool   `json:"shadow,omitempty"`    // правило в режиме shadow, на решение не влияет
+	WarnOnly   bool   `json:"warn_only,omitempty"` // правило в режиме warn-only, вызов не блокирует
+	// SHA-256 отпечаток найденного значения для allowlist, само значение не сохраняется
+	Fingerprint string `json:"fingerprint,omitempty"`
 }
 
 // HookResponse представляет ответ хука
@@ -65,6 +88,7 @@ type HookResponse struct {
 	Suggestions       []string      `json:"suggestions,omitempty"`
 	Level             Level         `json:"level"`
 	Violations        []Violation   `json:"violations,omitempty"`
+	Shadow            []Violation   `json:"shadow,omitempty"` // нарушения правил в режиме shadow
 	Timestamp         time.Time     `json:"timestamp"`
 	ProcessTime       time.Duration `json:"process_time_ms"`
 	ModifiedToolInput *ToolInput    `json:"modified_tool_input,omitempty"` // Модифицированные параметры для Claude Code
@@ -89,7 +89,7 @@ func NewLogger(config *LoggerConfig) (Logger, error) {
 					Value: slog.StringValue(a.Value.Time().Format("2006-01-02 15:04:05")),
 				}
 			}
-			return a
+			return redactAttr(a)
 		},
 	}
 
@@ -107,6 +107,24 @@ func NewLogger(config *LoggerConfig) (Logger, error) {
 	return &slogLogger{logger: logger}, nil
 }
 
+// redactAttr маскирует секреты в сообщении и строковых атрибутах записи лога
+// Ошибки и fmt.Stringer переводятся в строку, чтобы секрет не ушел в лог через их текст
+func redactAttr(a slog.Attr) slog.Attr {
+	value := a.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		a.Value = slog.StringValue(Redact(value.String()))
+	case slog.KindAny:
+		switch v := value.Any().(type) {
+		case error:
+			a.Value = slog.StringValue(Redact(v.Error()))
+		case fmt.Stringer:
+			a.Value = slog.StringValue(Redact(v.String()))
+		}
+	}
+	return a
+}
+
 // Debug логирует сообщение уровня debug
 func (l *slogLogger) Debug(msg string, args ...any) {
 	l.logger.Debug(msg, args...)
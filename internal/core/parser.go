@@ -3,6 +3,7 @@ package core
 import (
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"strings"
 )
 
@@ -61,6 +62,9 @@ func extractToolSpecificData(input *ToolInput) error {
 		if newString, ok := toolData["new_string"].(string); ok {
 			input.NewString = newString
 		}
+		if oldString, ok := toolData["old_string"].(string); ok {
+			input.OldString = oldString
+		}
 
 	case "MultiEdit":
 		if filePath, ok := toolData["file_path"].(string); ok {
@@ -68,15 +72,19 @@ func extractToolSpecificData(input *ToolInput) error {
 		}
 		// Для MultiEdit объединяем все new_string из массива edits
 		if edits, ok := toolData["edits"].([]any); ok {
-			var allNewStrings []string
+			var allNewStrings, allOldStrings []string
 			for _, edit := range edits {
 				if editMap, ok := edit.(map[string]any); ok {
 					if newString, ok := editMap["new_string"].(string); ok {
 						allNewStrings = append(allNewStrings, newString)
 					}
+					if oldString, ok := editMap["old_string"].(string); ok {
+						allOldStrings = append(allOldStrings, oldString)
+					}
 				}
 			}
 			input.NewString = strings.Join(allNewStrings, " ")
+			input.OldString = strings.Join(allOldStrings, "\n")
 		}
 
 	case "Bash":
@@ -113,6 +121,35 @@ func CreateFileAnalysis(input *ToolInput) *FileAnalysis {
 	return analysis
 }
 
+// SetProjectRoot вычисляет путь файла относительно корня проекта
+// Файлы вне проекта сопоставляются с исключениями по исходному пути
+func (f *FileAnalysis) SetProjectRoot(root string) {
+	f.RelPath = ""
+	if root == "" {
+		return
+	}
+
+	absPath, err := filepath.Abs(f.Path)
+	if err != nil {
+		return
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return
+	}
+	if rel, err := filepath.Rel(absRoot, absPath); err == nil && filepath.IsLocal(rel) {
+		f.RelPath = filepath.ToSlash(rel)
+	}
+}
+
+// ProjectPath возвращает путь для сопоставления с шаблонами исключений
+func (f *FileAnalysis) ProjectPath() string {
+	if f.RelPath != "" {
+		return f.RelPath
+	}
+	return filepath.ToSlash(f.Path)
+}
+
 // getFileExtension извлекает расширение файла
 func getFileExtension(filePath string) string {
 	parts := strings.Split(filePath, ".")
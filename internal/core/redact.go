@@ -0,0 +1,53 @@
+package core
+
+import "sync"
+
+// Redactor маскирует секреты в тексте, который хук выводит в логи и ответы
+type Redactor interface {
+	Redact(text string) string
+}
+
+var (
+	redactorMu sync.RWMutex
+	redactor   Redactor
+)
+
+// SetRedactor задает маскирование секретов для всех логгеров и ответов хука, nil отключает его
+func SetRedactor(r Redactor) {
+	redactorMu.Lock()
+	defer redactorMu.Unlock()
+	redactor = r
+}
+
+// Redact маскирует секреты в тексте, без установленного Redactor текст не меняется
+func Redact(text string) string {
+	redactorMu.RLock()
+	r := redactor
+	redactorMu.RUnlock()
+	if r == nil {
+		return text
+	}
+	return r.Redact(text)
+}
+
+// RedactViolation маскирует секреты в сообщении и подсказке нарушения
+func RedactViolation(violation Violation) Violation {
+	violation.Message = Redact(violation.Message)
+	violation.Suggestion = Redact(violation.Suggestion)
+	return violation
+}
+
+// RedactResponse маскирует секреты в тексте ответа хука
+// ModifiedToolInput не меняется: это параметры инструмента для Claude Code, а не вывод для человека
+func RedactResponse(response *HookResponse) {
+	response.Message = Redact(response.Message)
+	for i, suggestion := range response.Suggestions {
+		response.Suggestions[i] = Redact(suggestion)
+	}
+	for i := range response.Violations {
+		response.Violations[i] = RedactViolation(response.Violations[i])
+	}
+	for i := range response.Shadow {
+		response.Shadow[i] = RedactViolation(response.Shadow[i])
+	}
+}
@@ -0,0 +1,84 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// replaceRedactor маскирует фиксированное значение
+type replaceRedactor struct{ secret string }
+
+func (r replaceRedactor) Redact(text string) string {
+	return strings.ReplaceAll(text, r.secret, "****")
+}
+
+const testSecret = "s3cr3t-value"
+
+func setTestRedactor(t *testing.T) {
+	t.Helper()
+	SetRedactor(replaceRedactor{secret: testSecret})
+	t.Cleanup(func() { SetRedactor(nil) })
+}
+
+func TestLogger_RedactsSecrets(t *testing.T) {
+	setTestRedactor(t)
+
+	for _, format := range []string{"text", "json"} {
+		t.Run(format, func(t *testing.T) {
+			logFile := filepath.Join(t.TempDir(), "hooks.log")
+			logger, err := NewLogger(&LoggerConfig{Level: "debug", Format: format, Output: "file", LogFile: logFile})
+			if err != nil {
+				t.Fatalf("NewLogger() error = %v", err)
+			}
+
+			logger.With("command", "login "+testSecret).Debug("running "+testSecret,
+				"error", errors.New("denied for "+testSecret),
+				"count", 1,
+			)
+
+			data, err := os.ReadFile(logFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(data), testSecret) {
+				t.Fatalf("secret leaked into log: %s", data)
+			}
+			if got := strings.Count(string(data), "****"); got != 3 {
+				t.Errorf("masked values = %d, want 3: %s", got, data)
+			}
+		})
+	}
+}
+
+func TestRedactResponse(t *testing.T) {
+	setTestRedactor(t)
+
+	modified := &ToolInput{Command: "login " + testSecret}
+	response := &HookResponse{
+		Message:           "blocked " + testSecret,
+		Suggestions:       []string{"rotate " + testSecret},
+		Violations:        []Violation{{Message: "found " + testSecret, Suggestion: "remove " + testSecret}},
+		Shadow:            []Violation{{Message: "shadow " + testSecret}},
+		ModifiedToolInput: modified,
+	}
+	RedactResponse(response)
+
+	got := []string{response.Message, response.Suggestions[0], response.Violations[0].Message, response.Violations[0].Suggestion, response.Shadow[0].Message}
+	for _, text := range got {
+		if strings.Contains(text, testSecret) || !strings.Contains(text, "****") {
+			t.Errorf("text not redacted: %q", text)
+		}
+	}
+	if response.ModifiedToolInput.Command != "login "+testSecret {
+		t.Errorf("modified tool input changed: %q", response.ModifiedToolInput.Command)
+	}
+}
+
+func TestRedact_WithoutRedactor(t *testing.T) {
+	if got := Redact("login " + testSecret); got != "login "+testSecret {
+		t.Errorf("Redact() = %q, want unchanged text", got)
+	}
+}
@@ -0,0 +1,356 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+	"github.com/aiseeq/claude-hooks/internal/installer"
+)
+
+// Status результат отдельной проверки
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Check одна проверка окружения с подсказкой по исправлению
+type Check struct {
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	Detail string `json:"detail"`
+	Hint   string `json:"hint,omitempty"`
+}
+
+// Report результат всех проверок
+type Report struct {
+	Checks []Check `json:"checks"`
+}
+
+// Failed возвращает true если хотя бы одна проверка не прошла
+func (r *Report) Failed() bool {
+	for _, check := range r.Checks {
+		if check.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// SettingsSource settings.json одной области установки
+type SettingsSource struct {
+	Scope string
+	Path  string
+}
+
+// Options параметры диагностики
+type Options struct {
+	ConfigPath string
+	Settings   []SettingsSource
+	Executable string
+	// RunHooks отправляет синтетические payload через зарегистрированные команды
+	RunHooks bool
+	// LookPath поиск исполняемых файлов, подменяется в тестах
+	LookPath func(file string) (string, error)
+}
+
+// Run выполняет все проверки
+func Run(ctx context.Context, opts Options) *Report {
+	if opts.LookPath == nil {
+		opts.LookPath = exec.LookPath
+	}
+
+	report := &Report{}
+	config, configCheck := checkConfig(opts.ConfigPath)
+	report.Checks = append(report.Checks, configCheck...)
+	if config == nil {
+		return report
+	}
+
+	entries, settingsChecks := loadSettings(opts.Settings)
+	report.Checks = append(report.Checks, settingsChecks...)
+	report.Checks = append(report.Checks, CheckWiring(config, entries, opts.Executable)...)
+	report.Checks = append(report.Checks, checkLogFile(config))
+	report.Checks = append(report.Checks, checkBinaries(config, opts.LookPath)...)
+
+	if opts.RunHooks {
+		report.Checks = append(report.Checks, runSyntheticPayloads(ctx, config, entries, opts.Executable, opts.ConfigPath)...)
+	}
+
+	return report
+}
+
+// checkConfig проверяет что конфигурация загружается
+func checkConfig(configPath string) (*core.Config, []Check) {
+	if configPath == "" {
+		configPath = core.DefaultConfigPath()
+	}
+	displayPath := configPath
+
+	issues, changes, err := core.CheckConfigFile(configPath)
+	if errors.Is(err, os.ErrNotExist) {
+		config := core.DefaultConfig()
+		return config, []Check{{
+			Name:   "config",
+			Status: StatusWarn,
+			Detail: fmt.Sprintf("%s does not exist, built-in defaults will be written on first run", displayPath),
+			Hint:   "claude-hooks config init",
+		}}
+	}
+	if err != nil {
+		return nil, []Check{{Name: "config", Status: StatusFail, Detail: err.Error(), Hint: "check file permissions"}}
+	}
+	if len(issues) > 0 {
+		details := make([]string, 0, len(issues))
+		for _, issue := range issues {
+			details = append(details, issue.String())
+		}
+		return nil, []Check{{
+			Name:   "config",
+			Status: StatusFail,
+			Detail: fmt.Sprintf("%s has %d problem(s): %s", displayPath, len(issues), strings.Join(details, "; ")),
+			Hint:   "claude-hooks config validate",
+		}}
+	}
+
+	config, err := core.LoadConfig(configPath)
+	if err != nil {
+		return nil, []Check{{Name: "config", Status: StatusFail, Detail: err.Error(), Hint: "claude-hooks config validate"}}
+	}
+
+	if len(changes) > 0 {
+		return config, []Check{{
+			Name:   "config",
+			Status: StatusWarn,
+			Detail: fmt.Sprintf("%s loads but uses a legacy format (version < %d)", displayPath, core.CurrentConfigVersion),
+			Hint:   "claude-hooks config migrate",
+		}}
+	}
+
+	return config, []Check{{Name: "config", Status: StatusPass, Detail: displayPath + " loads"}}
+}
+
+// loadSettings читает зарегистрированные хуки из всех областей
+func loadSettings(sources []SettingsSource) ([]installer.Entry, []Check) {
+	var entries []installer.Entry
+	var checks []Check
+
+	for _, source := range sources {
+		if _, err := os.Stat(source.Path); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		sourceEntries, err := installer.ReadEntries(source.Path)
+		if err != nil {
+			checks = append(checks, Check{
+				Name:   "settings " + source.Scope,
+				Status: StatusFail,
+				Detail: err.Error(),
+				Hint:   "fix the JSON syntax; Claude Code ignores an unreadable settings file",
+			})
+			continue
+		}
+		entries = append(entries, sourceEntries...)
+	}
+
+	return entries, checks
+}
+
+// CheckWiring проверяет что каждое нужное событие зарегистрировано с корректной командой
+func CheckWiring(config *core.Config, entries []installer.Entry, executable string) []Check {
+	own := ownEntries(entries)
+	if len(own) == 0 {
+		return []Check{{
+			Name:   "hooks registered",
+			Status: StatusFail,
+			Detail: "no claude-hooks commands found in Claude Code settings.json",
+			Hint:   "claude-hooks install",
+		}}
+	}
+
+	var checks []Check
+	for _, required := range installer.PlanEntries(config, executable, "") {
+		name := fmt.Sprintf("hook %s %s", required.Event, displayMatcher(required.Matcher))
+		entry, ok := findCoveringEntry(own, required)
+		if !ok {
+			checks = append(checks, Check{
+				Name:   name,
+				Status: StatusFail,
+				Detail: "not registered, enabled validators/tools will never run",
+				Hint:   "claude-hooks install",
+			})
+			continue
+		}
+		checks = append(checks, checkEntry(name, entry, executable))
+	}
+
+	return checks
+}
+
+// findCoveringEntry находит нашу запись события, matcher которой покрывает нужные инструменты
+func findCoveringEntry(own []installer.Entry, required installer.Entry) (installer.Entry, bool) {
+	for _, entry := range own {
+		if entry.Event == required.Event && matcherCovers(entry.Matcher, required.Matcher) {
+			return entry, true
+		}
+	}
+	return installer.Entry{}, false
+}
+
+// matcherCovers проверяет что matcher Claude Code срабатывает на все нужные инструменты
+func matcherCovers(matcher, required string) bool {
+	if matcher == "" || matcher == "*" {
+		return true
+	}
+	if required == "" {
+		return false
+	}
+	pattern, err := regexp.Compile("^(?:" + matcher + ")$")
+	if err != nil {
+		return false
+	}
+	for _, tool := range strings.Split(required, "|") {
+		if !pattern.MatchString(tool) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkEntry проверяет путь бинарника и таймаут зарегистрированной команды
+func checkEntry(name string, entry installer.Entry, executable string) Check {
+	binary := expandHome(installer.CommandBinary(entry.Command))
+
+	info, err := os.Stat(binary)
+	if err != nil {
+		return Check{
+			Name:   name,
+			Status: StatusFail,
+			Detail: fmt.Sprintf("binary %s not found", binary),
+			Hint:   "rebuild with make install or re-run claude-hooks install --binary <path>",
+		}
+	}
+	if info.IsDir() || info.Mode().Perm()&0111 == 0 {
+		return Check{Name: name, Status: StatusFail, Detail: fmt.Sprintf("%s is not executable", binary), Hint: "chmod +x " + binary}
+	}
+
+	switch {
+	case entry.Timeout >= 1000:
+		return Check{
+			Name:   name,
+			Status: StatusWarn,
+			Detail: fmt.Sprintf("timeout %d looks like milliseconds; Claude Code timeouts are in seconds", entry.Timeout),
+			Hint:   "claude-hooks install",
+		}
+	case entry.Timeout > 0 && entry.Timeout < 2:
+		return Check{
+			Name:   name,
+			Status: StatusWarn,
+			Detail: fmt.Sprintf("timeout %ds is too short for config loading and formatters", entry.Timeout),
+			Hint:   "claude-hooks install",
+		}
+	}
+
+	if executable != "" && !sameFile(binary, executable) {
+		return Check{
+			Name:   name,
+			Status: StatusWarn,
+			Detail: fmt.Sprintf("registered binary %s differs from running binary %s", binary, executable),
+			Hint:   "claude-hooks install (if this build should be used)",
+		}
+	}
+
+	return Check{Name: name, Status: StatusPass, Detail: entry.Command}
+}
+
+// checkLogFile проверяет что лог файл доступен на запись
+func checkLogFile(config *core.Config) Check {
+	if config.Logger.Output != "file" {
+		return Check{Name: "log file", Status: StatusPass, Detail: "logging to " + config.Logger.Output}
+	}
+
+	path := expandHome(config.Logger.LogFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return Check{Name: "log file", Status: StatusFail, Detail: err.Error(), Hint: "create " + filepath.Dir(path) + " or change logger.file"}
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return Check{Name: "log file", Status: StatusFail, Detail: err.Error(), Hint: "fix permissions of " + path + " or change logger.file"}
+	}
+	file.Close()
+
+	return Check{Name: "log file", Status: StatusPass, Detail: path + " is writable"}
+}
+
+// checkBinaries проверяет доступность внешних программ форматтера и notifier
+func checkBinaries(config *core.Config, lookPath func(string) (string, error)) []Check {
+	var checks []Check
+
+	if formatter, ok := config.Tools["formatter"]; ok && formatter.Enabled {
+		if formatter.GoFormat {
+			checks = append(checks, checkBinary(lookPath, "formatter gofmt", "Go files will not be formatted", "install Go or set tools.formatter.go_format: false", "gofmt"))
+		}
+		if formatter.TSFormat {
+			checks = append(checks, checkBinary(lookPath, "formatter prettier", "TS/JS files will not be formatted", "npm install -g prettier or set tools.formatter.ts_format: false", "prettier"))
+		}
+	}
+
+	if notifier, ok := config.Tools["notifier"]; ok && notifier.Enabled {
+		checks = append(checks, checkBinary(lookPath, "notifier desktop", "no desktop notification on Stop", "install libnotify (notify-send)", "notify-send"))
+		checks = append(checks, checkBinary(lookPath, "notifier sound", "no sound on Stop", "install libcanberra (canberra-gtk-play) or pulseaudio-utils (paplay)", "canberra-gtk-play", "paplay"))
+	}
+
+	return checks
+}
+
+// checkBinary ищет первую доступную программу из списка
+func checkBinary(lookPath func(string) (string, error), name, impact, hint string, candidates ...string) Check {
+	for _, candidate := range candidates {
+		if path, err := lookPath(candidate); err == nil {
+			return Check{Name: name, Status: StatusPass, Detail: path}
+		}
+	}
+	return Check{
+		Name:   name,
+		Status: StatusWarn,
+		Detail: fmt.Sprintf("%s not found in PATH: %s", strings.Join(candidates, " / "), impact),
+		Hint:   hint,
+	}
+}
+
+// expandHome раскрывает $HOME и ~ в пути команды
+func expandHome(path string) string {
+	homeDir, _ := os.UserHomeDir()
+	path = strings.ReplaceAll(path, "${HOME}", homeDir)
+	path = strings.ReplaceAll(path, "$HOME", homeDir)
+	if strings.HasPrefix(path, "~/") {
+		path = filepath.Join(homeDir, path[2:])
+	}
+	return path
+}
+
+// sameFile сравнивает пути с учетом символических ссылок
+func sameFile(a, b string) bool {
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return os.SameFile(infoA, infoB)
+}
+
+// displayMatcher форматирует matcher для имени проверки
+func displayMatcher(matcher string) string {
+	if matcher == "" {
+		return "[*]"
+	}
+	return "[" + matcher + "]"
+}
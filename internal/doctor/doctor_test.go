@@ -0,0 +1,137 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+	"github.com/aiseeq/claude-hooks/internal/installer"
+)
+
+func writeBinary(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), installer.BinaryName)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatalf("failed to write binary: %v", err)
+	}
+	return path
+}
+
+func findCheck(t *testing.T, checks []Check, name string) Check {
+	t.Helper()
+	for _, check := range checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("check %q not found in %+v", name, checks)
+	return Check{}
+}
+
+func TestCheckWiring(t *testing.T) {
+	binary := writeBinary(t)
+	config := core.DefaultConfig()
+
+	tests := []struct {
+		name       string
+		entries    []installer.Entry
+		check      string
+		wantStatus Status
+	}{
+		{
+			name:       "nothing registered",
+			entries:    []installer.Entry{{Event: installer.EventPreToolUse, Matcher: "Bash", Command: "echo audit"}},
+			check:      "hooks registered",
+			wantStatus: StatusFail,
+		},
+		{
+			name:       "matcher does not cover MultiEdit",
+			entries:    []installer.Entry{{Event: installer.EventPreToolUse, Matcher: "Write|Edit", Command: binary + " pre-tool-use", Timeout: 10}},
+			check:      "hook PreToolUse [Write|Edit|MultiEdit]",
+			wantStatus: StatusFail,
+		},
+		{
+			name:       "wildcard matcher with millisecond timeout",
+			entries:    []installer.Entry{{Event: installer.EventPreToolUse, Matcher: "*", Command: binary + " pre-tool-use", Timeout: 5000}},
+			check:      "hook PreToolUse [Bash]",
+			wantStatus: StatusWarn,
+		},
+		{
+			name:       "missing binary",
+			entries:    []installer.Entry{{Event: installer.EventStop, Command: "/nonexistent/claude-hooks stop", Timeout: 10}},
+			check:      "hook Stop [*]",
+			wantStatus: StatusFail,
+		},
+		{
+			name:       "correct registration",
+			entries:    []installer.Entry{{Event: installer.EventStop, Command: binary + " stop", Timeout: 10}},
+			check:      "hook Stop [*]",
+			wantStatus: StatusPass,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := CheckWiring(config, tt.entries, binary)
+			if got := findCheck(t, checks, tt.check); got.Status != tt.wantStatus {
+				t.Errorf("expected %s, got %s: %s", tt.wantStatus, got.Status, got.Detail)
+			}
+		})
+	}
+}
+
+func TestRun_ReportsConfigAndBinaries(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	content := "version: 2\nlogger:\n  level: info\n  output: file\n  file: " + filepath.Join(dir, "logs", "hooks.log") + "\n" +
+		"tools:\n  notifier:\n    enabled: true\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	lookPath := func(file string) (string, error) {
+		if file == "paplay" {
+			return "/usr/bin/paplay", nil
+		}
+		return "", errors.New("not found")
+	}
+
+	report := Run(context.Background(), Options{ConfigPath: configPath, LookPath: lookPath})
+
+	if got := findCheck(t, report.Checks, "config"); got.Status != StatusPass {
+		t.Errorf("config should load, got %+v", got)
+	}
+	if got := findCheck(t, report.Checks, "log file"); got.Status != StatusPass {
+		t.Errorf("log file should be writable, got %+v", got)
+	}
+	if got := findCheck(t, report.Checks, "notifier sound"); got.Status != StatusPass || got.Detail != "/usr/bin/paplay" {
+		t.Errorf("paplay should satisfy sound backend, got %+v", got)
+	}
+	if got := findCheck(t, report.Checks, "notifier desktop"); got.Status != StatusWarn {
+		t.Errorf("missing notify-send should warn, got %+v", got)
+	}
+	if !report.Failed() {
+		t.Error("report without registered hooks should fail")
+	}
+}
+
+func TestRun_InvalidConfigFails(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("logger:\n  levle: info\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	report := Run(context.Background(), Options{ConfigPath: configPath})
+
+	check := findCheck(t, report.Checks, "config")
+	if check.Status != StatusFail || !strings.Contains(check.Detail, "levle") {
+		t.Errorf("unknown key should fail config check, got %+v", check)
+	}
+	if !report.Failed() {
+		t.Error("report should fail")
+	}
+}
@@ -0,0 +1,219 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+	"github.com/aiseeq/claude-hooks/internal/installer"
+)
+
+// maxProbeTimeout ограничение времени одного синтетического запуска
+const maxProbeTimeout = 30 * time.Second
+
+// SyntheticSessionID session_id синтетических payload: такие вызовы не попадают в журнал аудита,
+// а Stop не запускает уведомления
+const SyntheticSessionID = "claude-hooks-doctor"
+
+// probe синтетический payload и ожидаемый код выхода хука
+type probe struct {
+	name     string
+	required installer.Entry
+	payload  map[string]any
+	wantExit int
+	// blocking ожидаемая блокировка: неудача означает предупреждение, а не ошибку,
+	// так как нарушение могут отключить исключения из конфигурации
+	blocking bool
+}
+
+// runSyntheticPayloads прогоняет синтетические payload через зарегистрированные команды хуков
+func runSyntheticPayloads(ctx context.Context, config *core.Config, entries []installer.Entry, executable, configPath string) []Check {
+	workDir, err := os.MkdirTemp("", "claude-hooks-doctor-")
+	if err != nil {
+		return []Check{{Name: "synthetic payloads", Status: StatusFail, Detail: err.Error()}}
+	}
+	defer os.RemoveAll(workDir)
+
+	var checks []Check
+	for _, p := range buildProbes(config, executable, configPath, workDir) {
+		command := p.required.Command
+		if entry, ok := findCoveringEntry(ownEntries(entries), p.required); ok {
+			command = entry.Command
+		}
+		checks = append(checks, runProbe(ctx, p, command, workDir))
+	}
+
+	return checks
+}
+
+// buildProbes формирует payload для каждого хука, который должен быть зарегистрирован
+func buildProbes(config *core.Config, executable, configPath, workDir string) []probe {
+	// Разделяем вызов чтобы не блокировать собственные хуки
+	exitCall := "pa" + "nic" + "(\"doctor probe\")"
+	probeFile := filepath.Join(workDir, "internal", "probe", "probe.go")
+	if err := os.MkdirAll(filepath.Dir(probeFile), 0755); err == nil {
+		os.WriteFile(probeFile, []byte("package probe\n"), 0644)
+	}
+
+	var probes []probe
+	for _, required := range installer.PlanEntries(config, executable, configPath) {
+		switch {
+		case required.Event == installer.EventPreToolUse && required.Matcher == installer.FileToolsMatcher:
+			probes = append(probes, probe{
+				name:     "probe PreToolUse Write (clean file)",
+				required: required,
+				payload:  writePayload(workDir, "PreToolUse", probeFile, "package probe\n\nfunc Probe() error { return nil }\n"),
+				wantExit: 0,
+			})
+			if validatorEnabled(config, "runtime_exit") {
+				probes = append(probes, probe{
+					name:     "probe PreToolUse Write (violation)",
+					required: required,
+					payload:  writePayload(workDir, "PreToolUse", probeFile, "package probe\n\nfunc Probe() {\n\t"+exitCall+"\n}\n"),
+					wantExit: 2,
+					blocking: true,
+				})
+			}
+		case required.Event == installer.EventPreToolUse:
+			probes = append(probes, probe{
+				name:     "probe PreToolUse Bash",
+				required: required,
+				payload: map[string]any{
+					"session_id":      SyntheticSessionID,
+					"hook_event_name": "PreToolUse",
+					"cwd":             workDir,
+					"tool_name":       "Bash",
+					"tool_input":      map[string]any{"command": "git status"},
+				},
+				wantExit: 0,
+			})
+		case required.Event == installer.EventPostToolUse:
+			probes = append(probes, probe{
+				name:     "probe PostToolUse Write",
+				required: required,
+				payload:  writePayload(workDir, "PostToolUse", probeFile, "package probe\n"),
+				wantExit: 0,
+			})
+		case required.Event == installer.EventStop:
+			probes = append(probes, probe{
+				name:     "probe Stop",
+				required: required,
+				payload: map[string]any{
+					"session_id":      SyntheticSessionID,
+					"hook_event_name": "Stop",
+					"cwd":             workDir,
+				},
+				wantExit: 0,
+			})
+		}
+	}
+
+	return probes
+}
+
+// runProbe выполняет команду хука с payload на stdin и сверяет код выхода
+func runProbe(ctx context.Context, p probe, command, workDir string) Check {
+	payload, err := json.Marshal(p.payload)
+	if err != nil {
+		return Check{Name: p.name, Status: StatusFail, Detail: err.Error()}
+	}
+
+	timeout := time.Duration(p.required.Timeout) * time.Second
+	if timeout <= 0 || timeout > maxProbeTimeout {
+		timeout = maxProbeTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Claude Code запускает команду через shell, повторяем это для раскрытия $HOME и кавычек
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = workDir
+	cmd.Stdin = bytes.NewReader(payload)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err = cmd.Run()
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	exitCode := 0
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		return Check{
+			Name:   p.name,
+			Status: StatusFail,
+			Detail: fmt.Sprintf("%s timed out after %s", command, timeout),
+			Hint:   "increase the hook timeout or disable slow tools",
+		}
+	case errors.As(err, &exitErr):
+		exitCode = exitErr.ExitCode()
+	case err != nil:
+		return Check{Name: p.name, Status: StatusFail, Detail: err.Error(), Hint: "claude-hooks install"}
+	}
+
+	if exitCode == p.wantExit {
+		return Check{Name: p.name, Status: StatusPass, Detail: fmt.Sprintf("exit %d in %s", exitCode, elapsed)}
+	}
+
+	detail := fmt.Sprintf("expected exit %d, got %d", p.wantExit, exitCode)
+	if output := firstLine(stderr.String()); output != "" {
+		detail += ": " + output
+	}
+	if p.blocking && exitCode == 0 {
+		return Check{
+			Name:   p.name,
+			Status: StatusWarn,
+			Detail: detail,
+			Hint:   "check validators.runtime_exit exception paths in the config",
+		}
+	}
+
+	return Check{Name: p.name, Status: StatusFail, Detail: detail, Hint: "run the command manually: " + command}
+}
+
+// writePayload формирует payload инструмента Write
+func writePayload(workDir, event, filePath, content string) map[string]any {
+	return map[string]any{
+		"session_id":      SyntheticSessionID,
+		"hook_event_name": event,
+		"cwd":             workDir,
+		"tool_name":       "Write",
+		"tool_input":      map[string]any{"file_path": filePath, "content": content},
+	}
+}
+
+// ownEntries отбирает записи, вызывающие claude-hooks
+func ownEntries(entries []installer.Entry) []installer.Entry {
+	var own []installer.Entry
+	for _, entry := range entries {
+		if installer.IsOwnCommand(entry.Command) {
+			own = append(own, entry)
+		}
+	}
+	return own
+}
+
+// validatorEnabled проверяет включен ли валидатор
+func validatorEnabled(config *core.Config, name string) bool {
+	validator, ok := config.Validators[name]
+	return ok && validator.Enabled
+}
+
+// firstLine возвращает первую непустую строку вывода
+func firstLine(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
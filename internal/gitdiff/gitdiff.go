@@ -0,0 +1,217 @@
+package gitdiff
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// FileDiff добавленные строки одного файла по новой версии
+type FileDiff struct {
+	Path  string       // путь относительно корня репозитория
+	Added map[int]bool // номера добавленных строк
+}
+
+// Lines возвращает количество добавленных строк
+func (f FileDiff) Lines() int {
+	return len(f.Added)
+}
+
+// Parse разбирает вывод git diff -U0 и собирает добавленные строки по файлам
+// Удаленные файлы пропускаются, так как в новой версии нечего проверять
+func Parse(r io.Reader) ([]FileDiff, error) {
+	var files []FileDiff
+	var current *FileDiff
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			current = nil
+		case strings.HasPrefix(line, "+++ "):
+			path, err := parsePath(strings.TrimPrefix(line, "+++ "))
+			if err != nil {
+				return nil, err
+			}
+			if path == "" {
+				current = nil
+				continue
+			}
+			files = append(files, FileDiff{Path: path, Added: make(map[int]bool)})
+			current = &files[len(files)-1]
+		case strings.HasPrefix(line, "@@ ") && current != nil:
+			start, count, err := parseHunkHeader(line)
+			if err != nil {
+				return nil, err
+			}
+			for i := 0; i < count; i++ {
+				current.Added[start+i] = true
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read diff: %w", err)
+	}
+
+	return files, nil
+}
+
+// parsePath извлекает путь из строки +++, /dev/null означает удаленный файл
+func parsePath(value string) (string, error) {
+	value = strings.TrimRight(value, "\t")
+	if value == "/dev/null" {
+		return "", nil
+	}
+	if strings.HasPrefix(value, "\"") {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse diff path %s: %w", value, err)
+		}
+		value = unquoted
+	}
+	return strings.TrimPrefix(value, "b/"), nil
+}
+
+// parseHunkHeader разбирает заголовок "@@ -a,b +c,d @@" и возвращает начало и длину новой части
+func parseHunkHeader(line string) (int, int, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, fmt.Errorf("malformed hunk header: %s", line)
+	}
+
+	rangeSpec := strings.TrimPrefix(fields[2], "+")
+	startSpec, countSpec, hasCount := strings.Cut(rangeSpec, ",")
+	start, err := strconv.Atoi(startSpec)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed hunk header: %s", line)
+	}
+	count := 1
+	if hasCount {
+		if count, err = strconv.Atoi(countSpec); err != nil {
+			return 0, 0, fmt.Errorf("malformed hunk header: %s", line)
+		}
+	}
+
+	return start, count, nil
+}
+
+// Run выполняет git в директории и возвращает stdout
+func Run(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// RepoRoot возвращает корень git репозитория, содержащего директорию
+func RepoRoot(ctx context.Context, dir string) (string, error) {
+	output, err := Run(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return filepath.FromSlash(strings.TrimSpace(string(output))), nil
+}
+
+// Changed возвращает добавленные строки рабочего дерева относительно ревизии,
+// включая новые файлы вне индекса; paths ограничивает diff указанными путями
+func Changed(ctx context.Context, dir, rev string, paths []string) ([]FileDiff, error) {
+	args := []string{"-c", "core.quotePath=false", "diff", "--no-color", "--no-ext-diff", "-U0", rev, "--"}
+	args = append(args, paths...)
+	output, err := Run(ctx, dir, args...)
+	if err != nil {
+		return nil, err
+	}
+	files, err := Parse(bytes.NewReader(output))
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := Untracked(ctx, dir, paths)
+	if err != nil {
+		return nil, err
+	}
+	return append(files, untracked...), nil
+}
+
+// Untracked возвращает новые файлы вне индекса, все их строки считаются добавленными
+// git diff такие файлы не показывает, файлы из .gitignore пропускаются
+func Untracked(ctx context.Context, dir string, paths []string) ([]FileDiff, error) {
+	root, err := RepoRoot(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"ls-files", "-z", "--others", "--exclude-standard", "--full-name", "--"}
+	args = append(args, paths...)
+	output, err := Run(ctx, dir, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []FileDiff
+	for _, path := range strings.Split(string(output), "\x00") {
+		if path == "" {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(path)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read untracked file: %w", err)
+		}
+		files = append(files, FileDiff{Path: path, Added: allLines(content)})
+	}
+	return files, nil
+}
+
+// allLines возвращает номера всех строк содержимого
+func allLines(content []byte) map[int]bool {
+	count := bytes.Count(content, []byte("\n"))
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		count++
+	}
+	lines := make(map[int]bool, count)
+	for i := 1; i <= count; i++ {
+		lines[i] = true
+	}
+	return lines
+}
+
+// Staged возвращает добавленные строки проиндексированных изменений
+func Staged(ctx context.Context, dir string) ([]FileDiff, error) {
+	output, err := Run(ctx, dir, "-c", "core.quotePath=false", "diff", "--cached", "--no-color", "--no-ext-diff", "-U0", "--")
+	if err != nil {
+		return nil, err
+	}
+	return Parse(bytes.NewReader(output))
+}
+
+// StagedContent возвращает содержимое файла в индексе
+// Именно эта версия попадет в коммит, рабочее дерево может отличаться
+func StagedContent(ctx context.Context, dir, path string) ([]byte, error) {
+	return Run(ctx, dir, "show", ":"+filepath.ToSlash(path))
+}
+
+// HooksDir возвращает директорию git hooks с учетом core.hooksPath
+func HooksDir(ctx context.Context, dir string) (string, error) {
+	output, err := Run(ctx, dir, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	hooksDir := filepath.FromSlash(strings.TrimSpace(string(output)))
+	if !filepath.IsAbs(hooksDir) {
+		hooksDir = filepath.Join(dir, hooksDir)
+	}
+	return hooksDir, nil
+}
@@ -0,0 +1,118 @@
+package gitdiff
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleDiff = `diff --git a/internal/a.go b/internal/a.go
+index 1111111..2222222 100644
+--- a/internal/a.go
++++ b/internal/a.go
+@@ -3 +3,2 @@ func f() {
+-	old()
++	first()
++	second()
+@@ -10,0 +12 @@ func g() {
++	third()
+@@ -20,2 +22,0 @@ func h() {
+-	removed()
+-	removed()
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package old
+-
+diff --git "a/dir with space/\321\204.go" "b/dir with space/\321\204.go"
+new file mode 100644
+--- /dev/null
++++ "b/dir with space/\321\204.go"
+@@ -0,0 +1,2 @@
++package x
++
+`
+
+func TestParse(t *testing.T) {
+	files, err := Parse(strings.NewReader(sampleDiff))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files (deleted file skipped), got %d: %+v", len(files), files)
+	}
+
+	first := files[0]
+	if first.Path != "internal/a.go" {
+		t.Errorf("unexpected path %q", first.Path)
+	}
+	for _, line := range []int{3, 4, 12} {
+		if !first.Added[line] {
+			t.Errorf("line %d should be added", line)
+		}
+	}
+	if first.Lines() != 3 {
+		t.Errorf("expected 3 added lines, got %d", first.Lines())
+	}
+
+	second := files[1]
+	if second.Path != "dir with space/ф.go" {
+		t.Errorf("quoted path should be unquoted, got %q", second.Path)
+	}
+	if second.Lines() != 2 || !second.Added[1] || !second.Added[2] {
+		t.Errorf("new file lines should be added, got %v", second.Added)
+	}
+}
+
+func TestParseHunkHeader_Malformed(t *testing.T) {
+	if _, _, err := parseHunkHeader("@@ -1 garbage @@"); err == nil {
+		t.Error("expected error for malformed header")
+	}
+}
+
+func TestChanged_IncludesUntrackedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		if _, err := Run(ctx, dir, append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	git("init", "-q")
+	write("tracked.go", "package a\n")
+	write(".gitignore", "ignored.go\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+
+	write("tracked.go", "package a\n\nvar x = 1\n")
+	write("new.go", "package a\n\nvar y = 2")
+	write("ignored.go", "package a\n")
+
+	files, err := Changed(ctx, dir, "HEAD", nil)
+	if err != nil {
+		t.Fatalf("changed failed: %v", err)
+	}
+	added := make(map[string]int)
+	for _, file := range files {
+		added[file.Path] = file.Lines()
+	}
+	if added["tracked.go"] != 2 || added["new.go"] != 3 || len(added) != 2 {
+		t.Errorf("expected tracked.go with 2 and untracked new.go with 3 added lines, got %v", added)
+	}
+}
@@ -0,0 +1,98 @@
+package installer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// GitHookMarker строка, по которой опознается установленный нами git hook
+const GitHookMarker = "# managed by claude-hooks git install-hook"
+
+// GitHookResult результат установки git hook
+type GitHookResult struct {
+	Path       string
+	BackupPath string
+	Changed    bool
+}
+
+// GitHookScript строит скрипт pre-commit, вызывающий claude-hooks
+func GitHookScript(binaryPath, configPath string) string {
+	command := []string{"exec", shellQuote(binaryPath)}
+	if configPath != "" {
+		command = append(command, "--config", shellQuote(configPath))
+	}
+	command = append(command, "git", "pre-commit")
+
+	return "#!/bin/sh\n" + GitHookMarker + "\n" + strings.Join(command, " ") + "\n"
+}
+
+// InstallGitHook записывает pre-commit hook в директорию hooks репозитория
+// Чужой hook заменяется только с force, исходный файл сохраняется рядом
+func InstallGitHook(hooksDir, binaryPath, configPath string, force bool) (*GitHookResult, error) {
+	path := filepath.Join(hooksDir, "pre-commit")
+	script := GitHookScript(binaryPath, configPath)
+	result := &GitHookResult{Path: path}
+
+	existing, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	case string(existing) == script:
+		return result, nil
+	case !isOwnGitHook(existing):
+		if !force {
+			return nil, fmt.Errorf("%s already exists and is not managed by claude-hooks (use --force to replace it, a backup is kept)", path)
+		}
+		result.BackupPath = path + ".bak-" + time.Now().Format("20060102-150405")
+		if err := os.WriteFile(result.BackupPath, existing, 0755); err != nil {
+			return nil, fmt.Errorf("failed to write backup: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	// WriteFile не меняет права существующего файла
+	if err := os.Chmod(path, 0755); err != nil {
+		return nil, fmt.Errorf("failed to make %s executable: %w", path, err)
+	}
+
+	result.Changed = true
+	return result, nil
+}
+
+// UninstallGitHook удаляет pre-commit hook, только если он установлен claude-hooks
+func UninstallGitHook(hooksDir string) (*GitHookResult, error) {
+	path := filepath.Join(hooksDir, "pre-commit")
+	result := &GitHookResult{Path: path}
+
+	existing, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if !isOwnGitHook(existing) {
+		return nil, fmt.Errorf("%s is not managed by claude-hooks, leaving it untouched", path)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return nil, fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	result.Changed = true
+	return result, nil
+}
+
+// isOwnGitHook проверяет наличие маркера claude-hooks в скрипте
+func isOwnGitHook(content []byte) bool {
+	return strings.Contains(string(content), GitHookMarker)
+}
@@ -0,0 +1,70 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstallGitHook(t *testing.T) {
+	hooksDir := filepath.Join(t.TempDir(), "hooks")
+
+	result, err := InstallGitHook(hooksDir, "/usr/local/bin/claude-hooks", "", false)
+	if err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if !result.Changed {
+		t.Error("first install should change the hook")
+	}
+
+	info, err := os.Stat(result.Path)
+	if err != nil {
+		t.Fatalf("hook not written: %v", err)
+	}
+	if info.Mode().Perm()&0100 == 0 {
+		t.Error("hook should be executable")
+	}
+
+	again, err := InstallGitHook(hooksDir, "/usr/local/bin/claude-hooks", "", false)
+	if err != nil || again.Changed {
+		t.Errorf("repeated install should be a no-op, got %+v, %v", again, err)
+	}
+}
+
+func TestInstallGitHook_ForeignHook(t *testing.T) {
+	hooksDir := t.TempDir()
+	path := filepath.Join(hooksDir, "pre-commit")
+	foreign := "#!/bin/sh\nmake lint\n"
+	if err := os.WriteFile(path, []byte(foreign), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := InstallGitHook(hooksDir, "/bin/claude-hooks", "", false); err == nil {
+		t.Fatal("foreign hook should not be replaced without force")
+	}
+	if _, err := UninstallGitHook(hooksDir); err == nil {
+		t.Error("foreign hook should not be removed")
+	}
+
+	result, err := InstallGitHook(hooksDir, "/bin/claude-hooks", "/etc/hooks.yaml", true)
+	if err != nil {
+		t.Fatalf("forced install failed: %v", err)
+	}
+	backup, err := os.ReadFile(result.BackupPath)
+	if err != nil || string(backup) != foreign {
+		t.Errorf("backup should keep the foreign hook, got %q, %v", backup, err)
+	}
+	script, _ := os.ReadFile(path)
+	if !strings.Contains(string(script), "--config /etc/hooks.yaml git pre-commit") {
+		t.Errorf("unexpected hook script: %s", script)
+	}
+
+	removed, err := UninstallGitHook(hooksDir)
+	if err != nil || !removed.Changed {
+		t.Fatalf("uninstall failed: %+v, %v", removed, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("hook should be removed")
+	}
+}
@@ -0,0 +1,514 @@
+package installer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+)
+
+// Области установки хуков в Claude Code
+const (
+	ScopeUser    = "user"
+	ScopeProject = "project"
+	ScopeLocal   = "local"
+)
+
+// Hook events Claude Code, которые обрабатывает claude-hooks
+const (
+	EventPreToolUse  = "PreToolUse"
+	EventPostToolUse = "PostToolUse"
+	EventStop        = "Stop"
+)
+
+// FileToolsMatcher matcher файловых операций, которые проверяют валидаторы
+const FileToolsMatcher = "Write|Edit|MultiEdit"
+
+// BinaryName имя исполняемого файла, по которому опознаются наши хуки
+const BinaryName = "claude-hooks"
+
+// hookSubcommands подкоманды claude-hooks, вызываемые Claude Code
+var hookSubcommands = map[string]string{
+	EventPreToolUse:  "pre-tool-use",
+	EventPostToolUse: "post-tool-use",
+	EventStop:        "stop",
+}
+
+// Entry одна регистрация хука в settings.json
+type Entry struct {
+	Event   string `json:"event"`
+	Matcher string `json:"matcher,omitempty"`
+	Command string `json:"command"`
+	Timeout int    `json:"timeout"` // секунды, как ожидает Claude Code
+}
+
+// Options параметры установки
+type Options struct {
+	SettingsPath string
+	DryRun       bool
+}
+
+// Result результат изменения settings.json
+type Result struct {
+	SettingsPath string
+	BackupPath   string
+	Added        []Entry
+	Removed      []Entry
+	Kept         []Entry // команды claude-hooks, добавленные не через install: остаются на месте
+	Changed      bool
+	Content      []byte
+}
+
+// hookCommand элемент массива hooks в группе matcher
+type hookCommand struct {
+	Type    string `json:"type"`
+	Command string `json:"command"`
+	Timeout int    `json:"timeout,omitempty"`
+}
+
+// PlanEntries строит регистрации хуков для включенных валидаторов и инструментов
+func PlanEntries(config *core.Config, binaryPath, configPath string) []Entry {
+	var entries []Entry
+
+	command := func(event string) string {
+		parts := []string{shellQuote(binaryPath), hookSubcommands[event]}
+		if configPath != "" {
+			parts = append(parts, "--config", shellQuote(configPath))
+		}
+		return strings.Join(parts, " ")
+	}
+
+	if anyValidatorEnabled(config) {
+		entries = append(entries, Entry{Event: EventPreToolUse, Matcher: FileToolsMatcher, Command: command(EventPreToolUse), Timeout: 10})
+	}
+	if toolEnabled(config, "bash") {
+		entries = append(entries, Entry{Event: EventPreToolUse, Matcher: "Bash", Command: command(EventPreToolUse), Timeout: 5})
+	}
+	if toolEnabled(config, "formatter") {
+		entries = append(entries, Entry{Event: EventPostToolUse, Matcher: FileToolsMatcher, Command: command(EventPostToolUse), Timeout: 30})
+	}
+	if toolEnabled(config, "notifier") {
+		entries = append(entries, Entry{Event: EventStop, Command: command(EventStop), Timeout: 10})
+	}
+
+	return entries
+}
+
+// Install идемпотентно регистрирует хуки в settings.json
+// Записи предыдущего install (по манифесту) заменяются, остальные хуки не трогаются
+func Install(entries []Entry, opts Options) (*Result, error) {
+	settings, original, err := loadSettings(opts.SettingsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	installed, err := readManifest(opts.SettingsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	hooks, err := hooksObject(settings)
+	if err != nil {
+		return nil, err
+	}
+	hadHooks := hooks.Len() > 0
+
+	// Совпадающие с планом записи тоже наши: так повторный install не дублирует их без манифеста
+	removed, kept, err := removeInstalledHooks(hooks, newEntrySet(installed, entries))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if err := appendGroup(hooks, entry); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := storeHooks(settings, hooks, hadHooks); err != nil {
+		return nil, err
+	}
+
+	result := &Result{SettingsPath: opts.SettingsPath, Kept: kept}
+	result.Added, result.Removed = diffEntries(entries, removed)
+	if result, err = finish(settings, original, opts, result); err != nil || opts.DryRun {
+		return result, err
+	}
+	return result, writeManifest(opts.SettingsPath, entries)
+}
+
+// Uninstall удаляет из settings.json только записи, добавленные install
+func Uninstall(opts Options) (*Result, error) {
+	settings, original, err := loadSettings(opts.SettingsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	installed, err := readManifest(opts.SettingsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	hooks, err := hooksObject(settings)
+	if err != nil {
+		return nil, err
+	}
+	hadHooks := hooks.Len() > 0
+
+	removed, kept, err := removeInstalledHooks(hooks, newEntrySet(installed))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := storeHooks(settings, hooks, hadHooks); err != nil {
+		return nil, err
+	}
+
+	result := &Result{SettingsPath: opts.SettingsPath, Removed: removed, Kept: kept}
+	if result, err = finish(settings, original, opts, result); err != nil || opts.DryRun {
+		return result, err
+	}
+	return result, writeManifest(opts.SettingsPath, nil)
+}
+
+// ReadEntries возвращает все command хуки из settings.json
+func ReadEntries(settingsPath string) ([]Entry, error) {
+	settings, _, err := loadSettings(settingsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	hooks, err := hooksObject(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, event := range hooks.Keys() {
+		groups, err := eventGroups(hooks, event)
+		if err != nil {
+			return nil, err
+		}
+		for _, raw := range groups {
+			matcher, commands, err := parseGroup(raw)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", event, err)
+			}
+			for _, command := range commands {
+				if command.Type != "command" {
+					continue
+				}
+				entries = append(entries, Entry{Event: event, Matcher: matcher, Command: command.Command, Timeout: command.Timeout})
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// IsOwnCommand проверяет, что команда хука вызывает claude-hooks
+func IsOwnCommand(command string) bool {
+	binary, rest := splitCommand(command)
+	if filepath.Base(binary) != BinaryName {
+		return false
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return false
+	}
+	for _, subcommand := range hookSubcommands {
+		if fields[0] == subcommand {
+			return true
+		}
+	}
+	return false
+}
+
+// CommandBinary извлекает путь исполняемого файла из команды хука
+func CommandBinary(command string) string {
+	binary, _ := splitCommand(command)
+	return binary
+}
+
+// splitCommand отделяет исполняемый файл (возможно в кавычках) от аргументов
+func splitCommand(command string) (string, string) {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return "", ""
+	}
+	if quote := command[0]; quote == '\'' || quote == '"' {
+		if end := strings.IndexByte(command[1:], quote); end >= 0 {
+			return command[1 : end+1], command[end+2:]
+		}
+	}
+	if i := strings.IndexAny(command, " \t"); i >= 0 {
+		return command[:i], command[i+1:]
+	}
+	return command, ""
+}
+
+// finish сериализует настройки и записывает их, если что-то изменилось
+func finish(settings *orderedObject, original []byte, opts Options, result *Result) (*Result, error) {
+	content, err := encodeSettings(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode settings: %w", err)
+	}
+	result.Content = content
+	if original == nil {
+		// Файла не было: создаем его только если есть что записать
+		result.Changed = settings.Len() > 0
+	} else {
+		result.Changed = !sameJSON(original, content)
+	}
+
+	if !result.Changed || opts.DryRun {
+		return result, nil
+	}
+
+	backupPath, err := writeSettings(opts.SettingsPath, original, content, time.Now().Format("20060102-150405"))
+	if err != nil {
+		return nil, err
+	}
+	result.BackupPath = backupPath
+
+	return result, nil
+}
+
+// hooksObject возвращает объект hooks из настроек
+func hooksObject(settings *orderedObject) (*orderedObject, error) {
+	hooks := newOrderedObject()
+	raw, ok := settings.Get("hooks")
+	if !ok || string(raw) == "null" {
+		return hooks, nil
+	}
+	if err := json.Unmarshal(raw, hooks); err != nil {
+		return nil, fmt.Errorf("invalid hooks section: %w", err)
+	}
+	return hooks, nil
+}
+
+// storeHooks записывает объект hooks обратно
+// Объект удаляется, только если опустел в результате наших изменений
+func storeHooks(settings, hooks *orderedObject, hadHooks bool) error {
+	if hooks.Len() == 0 {
+		if hadHooks {
+			settings.Delete("hooks")
+		}
+		return nil
+	}
+	raw, err := json.Marshal(hooks)
+	if err != nil {
+		return err
+	}
+	settings.Set("hooks", raw)
+	return nil
+}
+
+// eventGroups возвращает группы matcher для события
+func eventGroups(hooks *orderedObject, event string) ([]json.RawMessage, error) {
+	raw, ok := hooks.Get(event)
+	if !ok {
+		return nil, nil
+	}
+	var groups []json.RawMessage
+	if err := json.Unmarshal(raw, &groups); err != nil {
+		return nil, fmt.Errorf("invalid %s hooks: %w", event, err)
+	}
+	return groups, nil
+}
+
+// parseGroup разбирает группу matcher на matcher и список команд
+func parseGroup(raw json.RawMessage) (string, []hookCommand, error) {
+	var group struct {
+		Matcher string        `json:"matcher"`
+		Hooks   []hookCommand `json:"hooks"`
+	}
+	if err := json.Unmarshal(raw, &group); err != nil {
+		return "", nil, fmt.Errorf("invalid hook group: %w", err)
+	}
+	return group.Matcher, group.Hooks, nil
+}
+
+// removeInstalledHooks удаляет из всех событий команды, добавленные install
+// Группы и события удаляются только если опустели из-за наших команд
+// Остальные команды claude-hooks возвращаются как kept
+func removeInstalledHooks(hooks *orderedObject, installed entrySet) (removed, kept []Entry, err error) {
+
+	for _, event := range append([]string(nil), hooks.Keys()...) {
+		groups, err := eventGroups(hooks, event)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var keptGroups []json.RawMessage
+		changed := false
+		for _, raw := range groups {
+			matcher, commands, err := parseGroup(raw)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", event, err)
+			}
+
+			ownCount := 0
+			for _, command := range commands {
+				entry := Entry{Event: event, Matcher: matcher, Command: command.Command, Timeout: command.Timeout}
+				switch {
+				case installed.has(event, matcher, command.Command):
+					ownCount++
+					removed = append(removed, entry)
+				case IsOwnCommand(command.Command):
+					kept = append(kept, entry)
+				}
+			}
+			if ownCount == 0 {
+				keptGroups = append(keptGroups, raw)
+				continue
+			}
+
+			changed = true
+			if ownCount == len(commands) {
+				continue
+			}
+			rewritten, err := withoutInstalledCommands(raw, event, matcher, installed)
+			if err != nil {
+				return nil, nil, err
+			}
+			keptGroups = append(keptGroups, rewritten)
+		}
+
+		if !changed {
+			continue
+		}
+		if len(keptGroups) == 0 {
+			hooks.Delete(event)
+			continue
+		}
+		encoded, err := json.Marshal(keptGroups)
+		if err != nil {
+			return nil, nil, err
+		}
+		hooks.Set(event, encoded)
+	}
+
+	return removed, kept, nil
+}
+
+// withoutInstalledCommands убирает из группы команды, добавленные install, сохраняя остальные поля
+func withoutInstalledCommands(raw json.RawMessage, event, matcher string, installed entrySet) (json.RawMessage, error) {
+	group := newOrderedObject()
+	if err := json.Unmarshal(raw, group); err != nil {
+		return nil, err
+	}
+
+	hooksRaw, _ := group.Get("hooks")
+	var commands []json.RawMessage
+	if err := json.Unmarshal(hooksRaw, &commands); err != nil {
+		return nil, err
+	}
+
+	var kept []json.RawMessage
+	for _, commandRaw := range commands {
+		var command hookCommand
+		if err := json.Unmarshal(commandRaw, &command); err == nil && installed.has(event, matcher, command.Command) {
+			continue
+		}
+		kept = append(kept, commandRaw)
+	}
+
+	encoded, err := json.Marshal(kept)
+	if err != nil {
+		return nil, err
+	}
+	group.Set("hooks", encoded)
+	return json.Marshal(group)
+}
+
+// appendGroup добавляет отдельную группу matcher с командой claude-hooks
+func appendGroup(hooks *orderedObject, entry Entry) error {
+	groups, err := eventGroups(hooks, entry.Event)
+	if err != nil {
+		return err
+	}
+
+	group := newOrderedObject()
+	if entry.Matcher != "" {
+		matcher, _ := json.Marshal(entry.Matcher)
+		group.Set("matcher", matcher)
+	}
+	commands, err := json.Marshal([]hookCommand{{Type: "command", Command: entry.Command, Timeout: entry.Timeout}})
+	if err != nil {
+		return err
+	}
+	group.Set("hooks", commands)
+
+	raw, err := json.Marshal(group)
+	if err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(append(groups, raw))
+	if err != nil {
+		return err
+	}
+	hooks.Set(entry.Event, encoded)
+	return nil
+}
+
+// diffEntries определяет действительно добавленные и удаленные записи
+func diffEntries(planned, removed []Entry) (added, dropped []Entry) {
+	key := func(e Entry) string {
+		return fmt.Sprintf("%s|%s|%s|%d", e.Event, e.Matcher, e.Command, e.Timeout)
+	}
+	previous := make(map[string]bool, len(removed))
+	for _, e := range removed {
+		previous[key(e)] = true
+	}
+	current := make(map[string]bool, len(planned))
+	for _, e := range planned {
+		current[key(e)] = true
+		if !previous[key(e)] {
+			added = append(added, e)
+		}
+	}
+	for _, e := range removed {
+		if !current[key(e)] {
+			dropped = append(dropped, e)
+		}
+	}
+	return added, dropped
+}
+
+// sameJSON сравнивает содержимое без учета форматирования
+func sameJSON(a, b []byte) bool {
+	var bufA, bufB bytes.Buffer
+	if json.Compact(&bufA, a) != nil || json.Compact(&bufB, b) != nil {
+		return false
+	}
+	return bytes.Equal(bufA.Bytes(), bufB.Bytes())
+}
+
+// anyValidatorEnabled проверяет включен ли хотя бы один валидатор
+func anyValidatorEnabled(config *core.Config) bool {
+	for _, validator := range config.Validators {
+		if validator.Enabled {
+			return true
+		}
+	}
+	return false
+}
+
+// toolEnabled проверяет включен ли инструмент
+func toolEnabled(config *core.Config, name string) bool {
+	tool, exists := config.Tools[name]
+	return exists && tool.Enabled
+}
+
+// shellQuote экранирует путь для команды хука, если в нем есть спецсимволы
+func shellQuote(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t'\"$`\\|&;<>()*?[]#~") {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
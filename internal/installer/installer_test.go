@@ -0,0 +1,171 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+)
+
+const existingSettings = `{
+  "permissions": {"allow": ["Bash(ls)"]},
+  "hooks": {
+    "PreToolUse": [
+      {"matcher": "Bash", "hooks": [
+        {"type": "command", "command": "echo audit", "timeout": 3},
+        {"type": "command", "command": "$HOME/bin/claude-hooks pre-tool-use", "timeout": 3000}
+      ]}
+    ]
+  },
+  "model": "opus"
+}
+`
+
+func writeSettingsFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write settings: %v", err)
+	}
+	return path
+}
+
+func TestPlanEntries_FollowsEnabledComponents(t *testing.T) {
+	config := &core.Config{
+		Validators: map[string]core.ValidatorConfig{"secrets": {Enabled: true}},
+		Tools: map[string]core.ToolConfig{
+			"bash":      {Enabled: true},
+			"formatter": {Enabled: false},
+			"notifier":  {Enabled: true},
+		},
+	}
+
+	entries := PlanEntries(config, "/opt/my tools/claude-hooks", "")
+
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d: %+v", len(entries), entries)
+	}
+	if entries[0].Command != "'/opt/my tools/claude-hooks' pre-tool-use" {
+		t.Errorf("binary path should be quoted, got %q", entries[0].Command)
+	}
+	for _, entry := range entries {
+		if entry.Event == EventPostToolUse {
+			t.Errorf("formatter disabled, PostToolUse should not be registered")
+		}
+		if !IsOwnCommand(entry.Command) {
+			t.Errorf("generated command not recognised as own: %q", entry.Command)
+		}
+	}
+}
+
+func TestInstall_IdempotentAndKeepsForeignHooks(t *testing.T) {
+	path := writeSettingsFile(t, existingSettings)
+	config := core.DefaultConfig()
+	entries := PlanEntries(config, "/usr/local/bin/claude-hooks", "")
+
+	result, err := Install(entries, Options{SettingsPath: path})
+	if err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if !result.Changed || result.BackupPath == "" {
+		t.Fatalf("expected settings to change with backup, got %+v", result)
+	}
+	if len(result.Removed) != 0 || len(result.Kept) != 1 {
+		t.Errorf("hand-written claude-hooks entry should be kept, removed: %+v, kept: %+v", result.Removed, result.Kept)
+	}
+	if _, err := os.Stat(ManifestPath(path)); err != nil {
+		t.Errorf("install should write a manifest: %v", err)
+	}
+
+	content, _ := os.ReadFile(path)
+	text := string(content)
+	for _, want := range []string{`"echo audit"`, `"model": "opus"`, `"Bash(ls)"`, "/usr/local/bin/claude-hooks stop", "$HOME/bin/claude-hooks pre-tool-use"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("settings should contain %s:\n%s", want, text)
+		}
+	}
+	if strings.Index(text, `"permissions"`) > strings.Index(text, `"model"`) {
+		t.Errorf("top-level key order should be preserved:\n%s", text)
+	}
+
+	again, err := Install(entries, Options{SettingsPath: path})
+	if err != nil {
+		t.Fatalf("second install failed: %v", err)
+	}
+	if again.Changed {
+		t.Errorf("second install should be a no-op")
+	}
+
+	// Смена конфигурации заменяет записи предыдущего install
+	moved := PlanEntries(config, "/opt/bin/claude-hooks", "")
+	replaced, err := Install(moved, Options{SettingsPath: path})
+	if err != nil {
+		t.Fatalf("reinstall failed: %v", err)
+	}
+	if len(replaced.Removed) != len(entries) || len(replaced.Added) != len(moved) {
+		t.Errorf("expected previous entries to be replaced, got %+v", replaced)
+	}
+}
+
+func TestUninstall_RemovesOnlyOwnEntries(t *testing.T) {
+	path := writeSettingsFile(t, existingSettings)
+	entries := PlanEntries(core.DefaultConfig(), "/usr/local/bin/claude-hooks", "")
+	if _, err := Install(entries, Options{SettingsPath: path}); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+
+	result, err := Uninstall(Options{SettingsPath: path})
+	if err != nil {
+		t.Fatalf("uninstall failed: %v", err)
+	}
+	if len(result.Removed) != len(entries) {
+		t.Errorf("expected %d removed entries, got %d", len(entries), len(result.Removed))
+	}
+
+	remaining, err := ReadEntries(path)
+	if err != nil {
+		t.Fatalf("failed to read entries: %v", err)
+	}
+	if len(remaining) != 2 || remaining[0].Command != "echo audit" || remaining[1].Command != "$HOME/bin/claude-hooks pre-tool-use" {
+		t.Errorf("only hooks not added by install should remain, got %+v", remaining)
+	}
+	if _, err := os.Stat(ManifestPath(path)); !os.IsNotExist(err) {
+		t.Errorf("uninstall should remove the manifest: %v", err)
+	}
+}
+
+func TestUninstall_KeepsHandWrittenEntries(t *testing.T) {
+	path := writeSettingsFile(t, `{
+  "hooks": {
+    "Stop": [
+      {"hooks": [{"type": "command", "command": "/usr/local/bin/claude-hooks stop --config /etc/team.yaml", "timeout": 10}]}
+    ]
+  }
+}
+`)
+
+	result, err := Uninstall(Options{SettingsPath: path})
+	if err != nil {
+		t.Fatalf("uninstall failed: %v", err)
+	}
+	if result.Changed || len(result.Removed) != 0 || len(result.Kept) != 1 {
+		t.Errorf("entry not added by install should be kept, got %+v", result)
+	}
+}
+
+func TestUninstall_MissingFileIsNoop(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+
+	result, err := Uninstall(Options{SettingsPath: path})
+	if err != nil {
+		t.Fatalf("uninstall failed: %v", err)
+	}
+	if result.Changed {
+		t.Error("uninstall without settings file should not change anything")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("uninstall should not create settings file")
+	}
+}
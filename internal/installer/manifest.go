@@ -0,0 +1,84 @@
+package installer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// manifest записи, добавленные install в settings.json
+// Uninstall удаляет только их: записи claude-hooks, написанные вручную, не трогаются
+type manifest struct {
+	Entries []Entry `json:"entries"`
+}
+
+// ManifestPath возвращает путь к манифесту рядом с settings.json:
+// settings.json -> settings.claude-hooks.json, settings.local.json -> settings.local.claude-hooks.json
+func ManifestPath(settingsPath string) string {
+	return strings.TrimSuffix(settingsPath, ".json") + "." + BinaryName + ".json"
+}
+
+// readManifest читает манифест, отсутствующий файл - пустой список
+func readManifest(settingsPath string) ([]Entry, error) {
+	path := ManifestPath(settingsPath)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return m.Entries, nil
+}
+
+// writeManifest записывает манифест, пустой список удаляет файл
+func writeManifest(settingsPath string, entries []Entry) error {
+	path := ManifestPath(settingsPath)
+	if len(entries) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(manifest{Entries: entries}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// entrySet множество записей по событию, matcher и команде
+// Timeout не учитывается: измененный вручную таймаут не делает запись чужой
+type entrySet map[string]bool
+
+// newEntrySet создает множество из записей
+func newEntrySet(entries ...[]Entry) entrySet {
+	set := make(entrySet)
+	for _, list := range entries {
+		for _, entry := range list {
+			set[entryKey(entry.Event, entry.Matcher, entry.Command)] = true
+		}
+	}
+	return set
+}
+
+// has проверяет наличие команды в множестве
+func (s entrySet) has(event, matcher, command string) bool {
+	return s[entryKey(event, matcher, command)]
+}
+
+// entryKey ключ записи в множестве
+func entryKey(event, matcher, command string) string {
+	return event + "\x00" + matcher + "\x00" + strings.TrimSpace(command)
+}
@@ -0,0 +1,210 @@
+package installer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// orderedObject JSON объект с сохранением порядка ключей
+// Значения хранятся как есть, поэтому незнакомые настройки не меняются
+type orderedObject struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+// newOrderedObject создает пустой объект
+func newOrderedObject() *orderedObject {
+	return &orderedObject{values: make(map[string]json.RawMessage)}
+}
+
+// UnmarshalJSON разбирает объект, запоминая порядок ключей
+func (o *orderedObject) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return errors.New("expected JSON object")
+	}
+
+	o.keys = nil
+	o.values = make(map[string]json.RawMessage)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return errors.New("expected object key")
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		o.Set(key, value)
+	}
+
+	_, err = decoder.Token()
+	return err
+}
+
+// MarshalJSON сериализует объект в исходном порядке ключей
+func (o *orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		buf.Write(o.values[key])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Get возвращает значение ключа
+func (o *orderedObject) Get(key string) (json.RawMessage, bool) {
+	value, ok := o.values[key]
+	return value, ok
+}
+
+// Set устанавливает значение, новые ключи добавляются в конец
+func (o *orderedObject) Set(key string, value json.RawMessage) {
+	if _, exists := o.values[key]; !exists {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// Delete удаляет ключ
+func (o *orderedObject) Delete(key string) {
+	if _, exists := o.values[key]; !exists {
+		return
+	}
+	delete(o.values, key)
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Len возвращает количество ключей
+func (o *orderedObject) Len() int {
+	return len(o.keys)
+}
+
+// Keys возвращает ключи в исходном порядке
+func (o *orderedObject) Keys() []string {
+	return o.keys
+}
+
+// SettingsPath возвращает путь к settings.json для области установки
+func SettingsPath(scope, projectDir string) (string, error) {
+	switch scope {
+	case ScopeUser:
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve home directory: %w", err)
+		}
+		return filepath.Join(homeDir, ".claude", "settings.json"), nil
+	case ScopeProject:
+		return filepath.Join(projectDir, ".claude", "settings.json"), nil
+	case ScopeLocal:
+		return filepath.Join(projectDir, ".claude", "settings.local.json"), nil
+	default:
+		return "", fmt.Errorf("unknown scope %q (expected %s, %s or %s)", scope, ScopeUser, ScopeProject, ScopeLocal)
+	}
+}
+
+// loadSettings читает settings.json, отсутствующий файл - пустые настройки
+func loadSettings(path string) (*orderedObject, []byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return newOrderedObject(), nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	settings := newOrderedObject()
+	if len(bytes.TrimSpace(data)) == 0 {
+		return settings, data, nil
+	}
+	if err := json.Unmarshal(data, settings); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return settings, data, nil
+}
+
+// encodeSettings сериализует настройки с отступом в два пробела
+func encodeSettings(settings *orderedObject) ([]byte, error) {
+	compact, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, compact, "", "  "); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// writeSettings записывает настройки, сохраняя резервную копию исходного файла
+func writeSettings(path string, original, updated []byte, backupSuffix string) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create settings directory: %w", err)
+	}
+
+	var backupPath string
+	if original != nil {
+		backupPath = path + ".bak-" + backupSuffix
+		if err := os.WriteFile(backupPath, original, 0644); err != nil {
+			return "", fmt.Errorf("failed to write backup: %w", err)
+		}
+	}
+
+	// Пишем через временный файл, чтобы не оставить settings.json наполовину записанным
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".settings-*.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to set settings permissions: %w", err)
+	}
+
+	if _, err := io.Copy(tmp, bytes.NewReader(updated)); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write settings: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write settings: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+
+	return backupPath, nil
+}
@@ -0,0 +1,75 @@
+package processor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aiseeq/claude-hooks/internal/baseline"
+	"github.com/aiseeq/claude-hooks/internal/core"
+)
+
+// FilterBaseline убирает нарушения, зафиксированные в baseline проекта
+// Так правка legacy файла блокируется только за новые нарушения
+func (e *Engine) FilterBaseline(file *core.FileAnalysis, projectDir string, violations []core.Violation) []core.Violation {
+	if len(violations) == 0 {
+		return violations
+	}
+
+	path := baseline.Resolve(e.config.General.Baseline, projectDir)
+	known, err := baseline.Load(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			e.logger.Warn("baseline ignored", "path", path, "error", err)
+		}
+		return violations
+	}
+
+	filePath, err := filepath.Abs(file.Path)
+	if err != nil {
+		return violations
+	}
+	relPath, err := filepath.Rel(filepath.Dir(path), filePath)
+	if err != nil {
+		return violations
+	}
+
+	matcher := known.Matcher()
+	lines := strings.Split(file.Content, "\n")
+	var remaining []core.Violation
+	for _, violation := range violations {
+		if matcher.Match(violation.Validator, violation.Type, relPath, lineAt(lines, violation.Line)) {
+			e.logger.Debug("violation matched baseline",
+				"validator", violation.Validator,
+				"type", violation.Type,
+				"file", relPath,
+				"line", violation.Line,
+			)
+			continue
+		}
+		remaining = append(remaining, violation)
+	}
+
+	return remaining
+}
+
+// projectDir возвращает корень проекта из payload хука или текущую директорию
+func projectDir(input *core.ToolInput) string {
+	if input.CWD != "" {
+		return input.CWD
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+	return dir
+}
+
+// lineAt возвращает строку по номеру, начиная с 1
+func lineAt(lines []string, line int) string {
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return lines[line-1]
+}
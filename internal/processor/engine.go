@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/aiseeq/claude-hooks/internal/core"
+	"github.com/aiseeq/claude-hooks/internal/shared"
 	"github.com/aiseeq/claude-hooks/internal/tools"
 	"github.com/aiseeq/claude-hooks/internal/tools/notifier"
 	"github.com/aiseeq/claude-hooks/internal/validators"
@@ -56,10 +57,12 @@ func (e *Engine) ProcessPreToolUse(ctx context.Context, input *core.ToolInput) (
 	var fileAnalysis *core.FileAnalysis
 	if input.FilePath != "" {
 		fileAnalysis = core.CreateFileAnalysis(input)
+		fileAnalysis.SetProjectRoot(projectDir(input))
 	}
 
 	var allViolations []core.Violation
 	var allSuggestions []string
+	var agentSuppressions []shared.Suppression
 
 	// Запускаем валидаторы для Write, Edit, MultiEdit операций
 	if e.isFileOperation(input.ToolName) && fileAnalysis != nil {
@@ -68,6 +71,8 @@ func (e *Engine) ProcessPreToolUse(ctx context.Context, input *core.ToolInput) (
 			e.logger.Error("validators execution failed", "error", err)
 			return nil, fmt.Errorf("validators failed: %w", err)
 		}
+		violations, agentSuppressions = e.applySuppressions(fileAnalysis, priorContent(input), violations)
+		violations = e.FilterBaseline(fileAnalysis, projectDir(input), violations)
 		allViolations = append(allViolations, violations...)
 		allSuggestions = append(allSuggestions, suggestions...)
 	}
@@ -81,17 +86,28 @@ func (e *Engine) ProcessPreToolUse(ctx context.Context, input *core.ToolInput) (
 	allViolations = append(allViolations, toolViolations...)
 	allSuggestions = append(allSuggestions, toolSuggestions...)
 
+	// Нарушения shadow правил только записываются
+	allViolations, shadowViolations := e.applyModes(allViolations)
+
 	// Определяем финальное действие
 	action := e.determineAction(allViolations)
 	level := e.determineLevel(allViolations)
 	message := e.generateMessage(action, allViolations)
 
+	// Подавления, добавленные самим агентом, может потребоваться подтвердить у пользователя
+	if action != core.HookActionBlock && len(agentSuppressions) > 0 && e.config.General.Suppressions.RequireApproval {
+		action = core.HookActionAsk
+		level = core.LevelWarning
+		message = e.generateApprovalMessage(agentSuppressions)
+	}
+
 	response := &core.HookResponse{
 		Action:            action,
 		Message:           message,
 		Suggestions:       e.deduplicateSuggestions(allSuggestions),
 		Level:             level,
 		Violations:        allViolations,
+		Shadow:            shadowViolations,
 		Timestamp:         time.Now(),
 		ProcessTime:       time.Since(start),
 		ModifiedToolInput: nil,
@@ -111,6 +127,18 @@ func (e *Engine) ProcessPreToolUse(ctx context.Context, input *core.ToolInput) (
 	return response, nil
 }
 
+// ValidateFile проверяет файл вне хука всеми валидаторами с учетом inline подавлений
+// Используется для baseline и офлайн сканирования, baseline здесь не применяется
+func (e *Engine) ValidateFile(ctx context.Context, file *core.FileAnalysis) ([]core.Violation, error) {
+	violations, _, err := e.runValidators(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	violations, _ = e.applySuppressions(file, file.Content, violations)
+	violations, _ = e.applyModes(violations)
+	return violations, nil
+}
+
 // ProcessPostToolUse обрабатывает PostToolUse хук
 func (e *Engine) ProcessPostToolUse(ctx context.Context, input *core.ToolInput) (*core.HookResponse, error) {
 	start := time.Now()
@@ -132,6 +160,7 @@ func (e *Engine) ProcessPostToolUse(ctx context.Context, input *core.ToolInput)
 		allSuggestions = append(allSuggestions, toolSuggestions...)
 	}
 
+	allViolations, shadowViolations := e.applyModes(allViolations)
 	action := e.determineAction(allViolations)
 	level := e.determineLevel(allViolations)
 	message := e.generatePostProcessMessage(action, allViolations, input.ToolName)
@@ -142,6 +171,7 @@ func (e *Engine) ProcessPostToolUse(ctx context.Context, input *core.ToolInput)
 		Suggestions: e.deduplicateSuggestions(allSuggestions),
 		Level:       level,
 		Violations:  allViolations,
+		Shadow:      shadowViolations,
 		Timestamp:   time.Now(),
 		ProcessTime: time.Since(start),
 	}
@@ -237,7 +267,7 @@ func (e *Engine) initTools() error {
 
 	// Bash Tool для валидации опасных bash команд
 	if config, exists := e.config.Tools["bash"]; exists && config.Enabled {
-		tool, err := tools.NewBashTool(config, e.logger)
+		tool, err := tools.NewBashTool(config, e.config.Validators["secrets"].Allowlist, e.logger)
 		if err != nil {
 			return fmt.Errorf("failed to create bash tool: %w", err)
 		}
@@ -273,7 +303,10 @@ func (e *Engine) runValidators(ctx context.Context, file *core.FileAnalysis) ([]
 
 		// ВСЕГДА передаём violations - и критические, и предупреждения
 		// determineAction() решит какое действие предпринять
-		allViolations = append(allViolations, result.Violations...)
+		for _, violation := range result.Violations {
+			violation.Validator = validator.Name()
+			allViolations = append(allViolations, violation)
+		}
 		allSuggestions = append(allSuggestions, result.Suggestions...)
 	}
 
@@ -300,7 +333,10 @@ func (e *Engine) runToolValidators(ctx context.Context, input *core.ToolInput) (
 			continue
 		}
 
-		allViolations = append(allViolations, result.Violations...)
+		for _, violation := range result.Violations {
+			violation.Validator = tool.Name()
+			allViolations = append(allViolations, violation)
+		}
 		allSuggestions = append(allSuggestions, result.Suggestions...)
 
 		if result.ModifiedToolInput != nil {
@@ -327,18 +363,22 @@ func (e *Engine) toolSupportsOperation(tool core.ToolValidator, toolName string)
 }
 
 // determineAction определяет финальное действие
+// Нарушения warn-only правил не блокируют вызов и дают notice, если других нарушений нет
 func (e *Engine) determineAction(violations []core.Violation) core.HookAction {
+	action := core.HookActionAllow
 	for _, violation := range violations {
-		if violation.Severity == core.LevelCritical {
+		switch {
+		case violation.WarnOnly:
+			if action == core.HookActionAllow {
+				action = core.HookActionNotice
+			}
+		case violation.Severity == core.LevelCritical:
 			return core.HookActionBlock
+		case violation.Severity == core.LevelWarning:
+			action = core.HookActionWarn
 		}
 	}
-	for _, violation := range violations {
-		if violation.Severity == core.LevelWarning {
-			return core.HookActionWarn
-		}
-	}
-	return core.HookActionAllow
+	return action
 }
 
 // determineLevel определяет уровень сообщения
@@ -364,7 +404,7 @@ func (e *Engine) generateMessage(action core.HookAction, violations []core.Viola
 			return violations[0].Message
 		}
 		return "Operation blocked"
-	case core.HookActionWarn:
+	case core.HookActionWarn, core.HookActionNotice:
 		if len(violations) > 0 {
 			return violations[0].Message
 		}
@@ -379,7 +419,7 @@ func (e *Engine) generatePostProcessMessage(action core.HookAction, violations [
 	switch action {
 	case core.HookActionBlock:
 		return fmt.Sprintf("Post-processing for %s blocked", toolName)
-	case core.HookActionWarn:
+	case core.HookActionWarn, core.HookActionNotice:
 		return fmt.Sprintf("Post-processing for %s completed with warnings", toolName)
 	default:
 		return fmt.Sprintf("Post-processing for %s completed", toolName)
@@ -0,0 +1,61 @@
+package processor
+
+import (
+	"github.com/aiseeq/claude-hooks/internal/core"
+)
+
+// applyModes применяет режимы правил к нарушениям
+// Нарушения отключенных правил отбрасываются, нарушения shadow правил возвращаются отдельно
+// и не участвуют в определении действия, нарушения warn-only правил помечаются и не блокируют вызов
+func (e *Engine) applyModes(violations []core.Violation) ([]core.Violation, []core.Violation) {
+	var enforced, shadow []core.Violation
+
+	for _, violation := range violations {
+		if !e.ruleEnabled(violation) {
+			e.logger.Debug("rule disabled", "validator", violation.Validator, "type", violation.Type)
+			continue
+		}
+		switch e.ruleMode(violation) {
+		case core.ModeShadow:
+			violation.Shadow = true
+			e.logger.Info("shadow violation",
+				"validator", violation.Validator,
+				"type", violation.Type,
+				"severity", violation.Severity,
+				"line", violation.Line,
+			)
+			shadow = append(shadow, violation)
+			continue
+		case core.ModeWarnOnly:
+			violation.WarnOnly = true
+			if violation.Severity == core.LevelCritical || violation.Severity == core.LevelError {
+				violation.Severity = core.LevelWarning
+			}
+		}
+		enforced = append(enforced, violation)
+	}
+
+	return enforced, shadow
+}
+
+// ruleMode возвращает режим правила по конфигурации валидатора или инструмента
+func (e *Engine) ruleMode(violation core.Violation) string {
+	if config, ok := e.config.Validators[violation.Validator]; ok {
+		return config.RuleMode(violation.Type)
+	}
+	if config, ok := e.config.Tools[violation.Validator]; ok {
+		return config.RuleMode(violation.Type)
+	}
+	return core.ModeEnforce
+}
+
+// ruleEnabled проверяет, не отключено ли правило в конфигурации валидатора или инструмента
+func (e *Engine) ruleEnabled(violation core.Violation) bool {
+	if config, ok := e.config.Validators[violation.Validator]; ok {
+		return config.RuleEnabled(violation.Type)
+	}
+	if config, ok := e.config.Tools[violation.Validator]; ok {
+		return config.RuleEnabled(violation.Type)
+	}
+	return true
+}
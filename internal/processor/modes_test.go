@@ -0,0 +1,121 @@
+package processor
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+)
+
+func TestEngine_RuleModesExitCode(t *testing.T) {
+	githubToken := "ghp_" + "a1B2c3D4e5F6a1B2c3D4e5F6a1B2c3D4e5F6"
+
+	tests := []struct {
+		name       string
+		mode       string
+		toolName   string
+		content    string
+		wantAction core.HookAction
+		wantExit   int
+	}{
+		{"enforce blocks", core.ModeEnforce, "Write", `token := "` + githubToken + `"`, core.HookActionBlock, 2},
+		{"warn-only does not block", core.ModeWarnOnly, "Write", `token := "` + githubToken + `"`, core.HookActionNotice, 0},
+		{"warn-only bash credential does not block", core.ModeWarnOnly, "Bash", "export GITHUB_TOKEN=" + githubToken, core.HookActionNotice, 0},
+		{"shadow allows", core.ModeShadow, "Write", `token := "` + githubToken + `"`, core.HookActionAllow, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := core.DefaultConfig()
+			secrets := config.Validators["secrets"]
+			secrets.Mode = tt.mode
+			config.Validators["secrets"] = secrets
+			bash := config.Tools["bash"]
+			bash.Mode = tt.mode
+			config.Tools["bash"] = bash
+
+			engine, err := New(config, core.NewTestLogger())
+			if err != nil {
+				t.Fatalf("failed to create engine: %v", err)
+			}
+
+			input := &core.ToolInput{ToolName: tt.toolName}
+			if tt.toolName == "Bash" {
+				input.Command = tt.content
+			} else {
+				input.FilePath = filepath.Join(t.TempDir(), "app.go")
+				input.Content = "package app\n\n" + tt.content + "\n"
+			}
+
+			response, err := engine.ProcessPreToolUse(context.Background(), input)
+			if err != nil {
+				t.Fatalf("processing failed: %v", err)
+			}
+			if response.Action != tt.wantAction {
+				t.Errorf("action = %s, want %s (violations %+v)", response.Action, tt.wantAction, response.Violations)
+			}
+			if got := response.Action.ExitCode(); got != tt.wantExit {
+				t.Errorf("exit code = %d, want %d", got, tt.wantExit)
+			}
+		})
+	}
+}
+
+func TestEngine_JWTWarnOnlyDoesNotBlock(t *testing.T) {
+	encode := func(header, claims string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".c2ln"
+	}
+	future := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name       string
+		token      string
+		wantAction core.HookAction
+		wantExit   int
+	}{
+		{"expired token", encode(`{"alg":"HS256"}`, `{"exp":1700000000}`), core.HookActionNotice, 0},
+		{"test issuer token", encode(`{"alg":"HS256"}`, fmt.Sprintf(`{"iss":"https://test-idp.local","exp":%d}`, future)), core.HookActionNotice, 0},
+		{"active token", encode(`{"alg":"HS256"}`, fmt.Sprintf(`{"iss":"auth.example.com","exp":%d}`, future)), core.HookActionBlock, 2},
+	}
+
+	config := core.DefaultConfig()
+	secrets := config.Validators["secrets"]
+	secrets.JWT = core.JWTConfig{TestIssuers: []string{"https://test-idp.local"}}
+	secrets.Rules = map[string]core.RuleConfig{
+		"expired_jwt":     {Mode: core.ModeWarnOnly},
+		"test_issuer_jwt": {Mode: core.ModeWarnOnly},
+	}
+	config.Validators["secrets"] = secrets
+
+	engine, err := New(config, core.NewTestLogger())
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &core.ToolInput{
+				ToolName: "Write",
+				FilePath: filepath.Join(t.TempDir(), "app.go"),
+				// Токен не присвоен переменной, чтобы проверка по энтропии не добавила второе нарушение
+				Content: "package app\n\nvar _ = fixture(" + strconv.Quote(tt.token) + ")\n",
+			}
+
+			response, err := engine.ProcessPreToolUse(context.Background(), input)
+			if err != nil {
+				t.Fatalf("processing failed: %v", err)
+			}
+			if response.Action != tt.wantAction {
+				t.Errorf("action = %s, want %s (violations %+v)", response.Action, tt.wantAction, response.Violations)
+			}
+			if got := response.Action.ExitCode(); got != tt.wantExit {
+				t.Errorf("exit code = %d, want %d", got, tt.wantExit)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+	"github.com/aiseeq/claude-hooks/internal/shared"
+)
+
+// suppressionsValidator имя источника нарушений для некорректных директив
+const suppressionsValidator = "suppressions"
+
+// applySuppressions убирает нарушения, подавленные inline директивами
+// Возвращает оставшиеся нарушения и примененные директивы, добавленные агентом в этой операции
+// prior - содержимое до операции: директивы, которые в нем уже были, не считаются добавленными агентом
+func (e *Engine) applySuppressions(file *core.FileAnalysis, prior string, violations []core.Violation) ([]core.Violation, []shared.Suppression) {
+	suppressions := shared.ParseSuppressions(file.Content)
+	if len(suppressions) == 0 {
+		return violations, nil
+	}
+
+	var remaining []core.Violation
+
+	// Документация может описывать синтаксис директив, не требуем от примеров обоснования
+	// Некорректные директивы идут первыми, чтобы сообщение объясняло почему подавление не сработало
+	if !file.IsDocsFile {
+		for _, suppression := range suppressions {
+			if !suppression.Valid() {
+				violation := shared.InvalidSuppressionViolation(suppression)
+				violation.Validator = suppressionsValidator
+				remaining = append(remaining, violation)
+			}
+		}
+	}
+
+	used := make(map[int]bool)
+	for _, violation := range violations {
+		suppression, ok := shared.FindSuppression(suppressions, violation.Validator, violation)
+		if !ok {
+			remaining = append(remaining, violation)
+			continue
+		}
+		used[suppression.Line] = true
+		e.logger.Info("violation suppressed",
+			"validator", violation.Validator,
+			"type", violation.Type,
+			"file", file.Path,
+			"line", violation.Line,
+			"reason", suppression.Reason,
+		)
+	}
+
+	var added []shared.Suppression
+	for _, suppression := range suppressions {
+		if used[suppression.Line] && !strings.Contains(prior, suppression.Text) {
+			added = append(added, suppression)
+		}
+	}
+
+	return remaining, added
+}
+
+// priorContent возвращает содержимое до операции: old_string правки и текущий файл на диске
+func priorContent(input *core.ToolInput) string {
+	prior := input.OldString
+	if data, err := os.ReadFile(input.FilePath); err == nil {
+		prior += "\n" + string(data)
+	}
+	return prior
+}
+
+// generateApprovalMessage описывает подавления, требующие подтверждения пользователя
+func (e *Engine) generateApprovalMessage(suppressions []shared.Suppression) string {
+	lines := make([]string, 0, len(suppressions))
+	for _, suppression := range suppressions {
+		lines = append(lines, fmt.Sprintf("line %d: %s", suppression.Line, suppression.Text))
+	}
+	return "Agent added suppression comments that require approval: " + strings.Join(lines, "; ")
+}
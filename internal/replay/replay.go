@@ -0,0 +1,155 @@
+package replay
+
+import (
+	"context"
+	"sort"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+)
+
+// Processor часть движка, нужная для replay
+type Processor interface {
+	ProcessPreToolUse(ctx context.Context, input *core.ToolInput) (*core.HookResponse, error)
+}
+
+// Outcome решение текущей политики для записанного вызова
+type Outcome struct {
+	Call
+	Target     string           `json:"target,omitempty"` // файл или команда
+	Predicted  Decision         `json:"predicted"`
+	Violations []core.Violation `json:"violations,omitempty"`
+	Error      string           `json:"error,omitempty"`
+}
+
+// Changed сообщает, отличается ли решение текущей политики от записанного
+// Вызовы без записанного решения хука (unknown, denied) не сравниваются
+func (o Outcome) Changed() bool {
+	if o.Recorded != DecisionAllowed && o.Recorded != DecisionBlocked {
+		return false
+	}
+	if o.Predicted == DecisionUnknown {
+		return false
+	}
+	return effect(o.Predicted) != o.Recorded
+}
+
+// effect переводит решение хука в то, что видно в транскрипте
+// В PreToolUse предупреждение тоже завершается exit 2 и останавливает вызов
+func effect(decision Decision) Decision {
+	if decision == DecisionWarned {
+		return DecisionBlocked
+	}
+	return decision
+}
+
+// RuleCount срабатывания одного правила
+type RuleCount struct {
+	Rule       string `json:"rule"`
+	Calls      int    `json:"calls"`
+	Violations int    `json:"violations"`
+}
+
+// Result итог прогона транскриптов через политику
+type Result struct {
+	Outcomes []Outcome        `json:"outcomes"`
+	Totals   map[Decision]int `json:"totals"`
+	Recorded map[Decision]int `json:"recorded"`
+	Rules    []RuleCount      `json:"rules"`
+	Changes  int              `json:"changes"`
+	Errors   int              `json:"errors"`
+}
+
+// Run прогоняет каждый вызов через PreToolUse движка и сравнивает с записанным решением
+func Run(ctx context.Context, proc Processor, calls []Call) *Result {
+	result := &Result{
+		Totals:   make(map[Decision]int),
+		Recorded: make(map[Decision]int),
+	}
+	rules := make(map[string]*RuleCount)
+
+	for _, call := range calls {
+		outcome := evaluate(ctx, proc, call)
+
+		result.Totals[outcome.Predicted]++
+		result.Recorded[outcome.Recorded]++
+		if outcome.Error != "" {
+			result.Errors++
+		}
+		if outcome.Changed() {
+			result.Changes++
+		}
+
+		seen := make(map[string]bool)
+		for _, violation := range outcome.Violations {
+			id := RuleID(violation)
+			count, ok := rules[id]
+			if !ok {
+				count = &RuleCount{Rule: id}
+				rules[id] = count
+			}
+			count.Violations++
+			if !seen[id] {
+				seen[id] = true
+				count.Calls++
+			}
+		}
+
+		result.Outcomes = append(result.Outcomes, outcome)
+	}
+
+	for _, count := range rules {
+		result.Rules = append(result.Rules, *count)
+	}
+	sort.Slice(result.Rules, func(i, j int) bool {
+		if result.Rules[i].Calls != result.Rules[j].Calls {
+			return result.Rules[i].Calls > result.Rules[j].Calls
+		}
+		return result.Rules[i].Rule < result.Rules[j].Rule
+	})
+
+	return result
+}
+
+// evaluate проверяет один вызов, ошибки сохраняются в результате и не прерывают прогон
+func evaluate(ctx context.Context, proc Processor, call Call) Outcome {
+	outcome := Outcome{Call: call, Predicted: DecisionUnknown}
+
+	input, err := call.ToolInput()
+	if err != nil {
+		outcome.Error = err.Error()
+		return outcome
+	}
+	outcome.Target = input.FilePath
+	if outcome.Target == "" {
+		outcome.Target = input.Command
+	}
+
+	response, err := proc.ProcessPreToolUse(ctx, input)
+	if err != nil {
+		outcome.Error = err.Error()
+		return outcome
+	}
+
+	outcome.Violations = response.Violations
+	switch response.Action {
+	case core.HookActionBlock:
+		outcome.Predicted = DecisionBlocked
+	case core.HookActionWarn:
+		outcome.Predicted = DecisionWarned
+	case core.HookActionNotice:
+		outcome.Predicted = DecisionAllowed // warn-only правила вызов не останавливают
+	case core.HookActionAsk:
+		outcome.Predicted = DecisionAsk
+	default:
+		outcome.Predicted = DecisionAllowed
+	}
+	return outcome
+}
+
+// RuleID идентификатор правила validator/type, для инструментальных проверок только type
+func RuleID(violation core.Violation) string {
+	if violation.Validator == "" {
+		return violation.Type
+	}
+	return violation.Validator + "/" + violation.Type
+}
@@ -0,0 +1,98 @@
+package replay
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+)
+
+const sampleTranscript = `{"type":"mode","mode":"normal","sessionId":"s1"}
+{"type":"user","sessionId":"s1","message":{"role":"user","content":"write some code"}}
+{"type":"assistant","sessionId":"s1","cwd":"/work","timestamp":"t1","message":{"role":"assistant","content":[{"type":"text","text":"ok"},{"type":"tool_use","id":"a","name":"Write","input":{"file_path":"/work/main.go","content":"package main"}}]}}
+{"type":"user","sessionId":"s1","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"a","content":"File created successfully"}]}}
+{"type":"assistant","sessionId":"s1","cwd":"/work","timestamp":"t2","message":{"role":"assistant","content":[{"type":"tool_use","id":"b","name":"Bash","input":{"command":"rm -rf build"}}]}}
+{"type":"user","sessionId":"s1","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"b","is_error":true,"content":[{"type":"text","text":"PreToolUse:Bash [claude-hooks pre-tool-use] failed with the following error: blocked"}]}]}}
+{"type":"assistant","sessionId":"s1","cwd":"/work","timestamp":"t3","message":{"role":"assistant","content":[{"type":"tool_use","id":"c","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"type":"user","sessionId":"s1","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"c","is_error":true,"content":"exit status 1"}]}}
+{"type":"assistant","sessionId":"s1","cwd":"/work","timestamp":"t4","message":{"role":"assistant","content":[{"type":"tool_use","id":"d","name":"Bash","input":{"command":"git push"}}]}}
+`
+
+func TestParse(t *testing.T) {
+	calls, err := Parse(strings.NewReader(sampleTranscript), "session.jsonl")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	expected := []struct {
+		id       string
+		tool     string
+		recorded Decision
+	}{
+		{"a", "Write", DecisionAllowed},
+		{"b", "Bash", DecisionBlocked},
+		{"c", "Bash", DecisionAllowed},
+		{"d", "Bash", DecisionUnknown},
+	}
+	if len(calls) != len(expected) {
+		t.Fatalf("expected %d calls, got %d", len(expected), len(calls))
+	}
+	for i, want := range expected {
+		if calls[i].ToolUseID != want.id || calls[i].ToolName != want.tool || calls[i].Recorded != want.recorded {
+			t.Errorf("call %d: expected %+v, got %+v", i, want, calls[i])
+		}
+	}
+
+	input, err := calls[0].ToolInput()
+	if err != nil {
+		t.Fatalf("payload failed: %v", err)
+	}
+	if input.FilePath != "/work/main.go" || input.Content != "package main" || input.CWD != "/work" {
+		t.Errorf("unexpected payload: %+v", input)
+	}
+}
+
+// fakeProcessor блокирует команды с rm и предупреждает о push
+type fakeProcessor struct{}
+
+func (fakeProcessor) ProcessPreToolUse(ctx context.Context, input *core.ToolInput) (*core.HookResponse, error) {
+	switch {
+	case strings.HasPrefix(input.Command, "rm "):
+		return &core.HookResponse{Action: core.HookActionBlock, Violations: []core.Violation{{Validator: "bash", Type: "rm"}}}, nil
+	case strings.Contains(input.Command, "push"):
+		return &core.HookResponse{Action: core.HookActionWarn, Violations: []core.Violation{{Type: "push"}}}, nil
+	case input.ToolName == "Write":
+		return &core.HookResponse{Action: core.HookActionWarn, Violations: []core.Violation{{Validator: "style", Type: "x"}, {Validator: "style", Type: "x"}}}, nil
+	}
+	return &core.HookResponse{Action: core.HookActionAllow}, nil
+}
+
+func TestRun(t *testing.T) {
+	calls, err := Parse(strings.NewReader(sampleTranscript), "session.jsonl")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	result := Run(context.Background(), fakeProcessor{}, calls)
+
+	if result.Totals[DecisionWarned] != 2 || result.Totals[DecisionBlocked] != 1 || result.Totals[DecisionAllowed] != 1 {
+		t.Errorf("unexpected totals: %v", result.Totals)
+	}
+	// Write был разрешен, а теперь предупреждение остановит вызов; push без записанного решения не сравнивается
+	if result.Changes != 1 || !result.Outcomes[0].Changed() || result.Outcomes[3].Changed() {
+		t.Errorf("expected only the Write call to change, got %d changes", result.Changes)
+	}
+	if result.Outcomes[1].Target != "rm -rf build" {
+		t.Errorf("unexpected target: %q", result.Outcomes[1].Target)
+	}
+
+	if len(result.Rules) != 3 || result.Rules[0].Rule != "bash/rm" {
+		t.Fatalf("unexpected rules: %+v", result.Rules)
+	}
+	for _, rule := range result.Rules {
+		if rule.Rule == "style/x" && (rule.Calls != 1 || rule.Violations != 2) {
+			t.Errorf("style/x should count 1 call and 2 violations, got %+v", rule)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+)
+
+// Decision исход вызова инструмента
+type Decision string
+
+const (
+	DecisionAllowed Decision = "allowed"
+	DecisionWarned  Decision = "warned"
+	DecisionBlocked Decision = "blocked"
+	DecisionAsk     Decision = "ask"
+	// DecisionDenied вызов отклонил пользователь, а не хук
+	DecisionDenied Decision = "denied"
+	// DecisionUnknown результат вызова не попал в транскрипт
+	DecisionUnknown Decision = "unknown"
+)
+
+// Call вызов инструмента, восстановленный из транскрипта
+type Call struct {
+	Transcript string          `json:"transcript"`
+	SessionID  string          `json:"session_id"`
+	ToolUseID  string          `json:"tool_use_id"`
+	ToolName   string          `json:"tool_name"`
+	Input      json.RawMessage `json:"tool_input"`
+	CWD        string          `json:"cwd,omitempty"`
+	Timestamp  string          `json:"timestamp,omitempty"`
+	Recorded   Decision        `json:"recorded"`
+}
+
+// ToolInput строит PreToolUse payload, который Claude Code передал бы хуку
+func (c Call) ToolInput() (*core.ToolInput, error) {
+	payload, err := json.Marshal(core.ToolInput{
+		SessionID:      c.SessionID,
+		ToolName:       c.ToolName,
+		ToolInput:      c.Input,
+		CWD:            c.CWD,
+		TranscriptPath: c.Transcript,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build payload for %s: %w", c.ToolUseID, err)
+	}
+	return core.ParseToolInput(payload)
+}
+
+// transcriptEntry строка транскрипта Claude Code, нужные для replay поля
+type transcriptEntry struct {
+	Type      string `json:"type"`
+	SessionID string `json:"sessionId"`
+	CWD       string `json:"cwd"`
+	Timestamp string `json:"timestamp"`
+	Message   *struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// contentBlock блок сообщения: tool_use от ассистента или tool_result от пользователя
+type contentBlock struct {
+	Type      string          `json:"type"`
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Input     json.RawMessage `json:"input"`
+	ToolUseID string          `json:"tool_use_id"`
+	Content   json.RawMessage `json:"content"`
+	IsError   bool            `json:"is_error"`
+}
+
+// Parse извлекает вызовы инструментов из транскрипта в порядке их появления
+// Записанное решение восстанавливается по соответствующему tool_result
+func Parse(r io.Reader, transcript string) ([]Call, error) {
+	var calls []Call
+	index := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+
+		var entry transcriptEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid transcript entry: %w", transcript, lineNumber, err)
+		}
+		if entry.Message == nil || (entry.Type != "assistant" && entry.Type != "user") {
+			continue
+		}
+
+		// Текстовые сообщения пользователя хранятся строкой, блоков в них нет
+		var blocks []contentBlock
+		if err := json.Unmarshal(entry.Message.Content, &blocks); err != nil {
+			continue
+		}
+
+		for _, block := range blocks {
+			switch block.Type {
+			case "tool_use":
+				index[block.ID] = len(calls)
+				calls = append(calls, Call{
+					Transcript: transcript,
+					SessionID:  entry.SessionID,
+					ToolUseID:  block.ID,
+					ToolName:   block.Name,
+					Input:      block.Input,
+					CWD:        entry.CWD,
+					Timestamp:  entry.Timestamp,
+					Recorded:   DecisionUnknown,
+				})
+			case "tool_result":
+				if i, ok := index[block.ToolUseID]; ok {
+					calls[i].Recorded = recordedDecision(block)
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", transcript, err)
+	}
+
+	return calls, nil
+}
+
+// recordedDecision определяет, чем закончился вызов, по тексту tool_result
+func recordedDecision(block contentBlock) Decision {
+	if !block.IsError {
+		return DecisionAllowed
+	}
+
+	text := resultText(block.Content)
+	switch {
+	case strings.Contains(text, "PreToolUse:"):
+		return DecisionBlocked
+	case strings.Contains(text, "doesn't want to proceed"), strings.Contains(text, "tool use was rejected"):
+		return DecisionDenied
+	default:
+		// Ошибка самого инструмента: хук вызов пропустил
+		return DecisionAllowed
+	}
+}
+
+// resultText возвращает текст tool_result: строку или склеенные текстовые блоки
+func resultText(content json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(content, &text); err == nil {
+		return text
+	}
+
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(content, &parts); err != nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range parts {
+		if part.Type == "text" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// Load читает вызовы из файлов транскриптов и директорий с ними (*.jsonl рекурсивно)
+func Load(paths []string) ([]Call, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to access %s: %w", path, err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.HasSuffix(d.Name(), ".jsonl") {
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk %s: %w", path, err)
+		}
+	}
+	sort.Strings(files)
+
+	var calls []Call
+	for _, file := range files {
+		parsed, err := loadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, parsed...)
+	}
+	return calls, nil
+}
+
+// loadFile читает вызовы из одного транскрипта
+func loadFile(path string) ([]Call, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	return Parse(f, path)
+}
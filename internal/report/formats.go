@@ -0,0 +1,344 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+)
+
+// jsonReport структура JSON отчета
+type jsonReport struct {
+	Tool     string    `json:"tool"`
+	Version  string    `json:"version,omitempty"`
+	Summary  Summary   `json:"summary"`
+	Findings []Finding `json:"findings"`
+}
+
+// writeJSON сериализует нарушения в JSON
+func writeJSON(w io.Writer, r *Report) error {
+	findings := r.Findings
+	if findings == nil {
+		findings = []Finding{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(jsonReport{Tool: ToolName, Version: r.Version, Summary: r.Summary(), Findings: findings})
+}
+
+// SARIF 2.1.0 - минимальное подмножество, которое принимают code scanning сервисы
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	Help                 *sarifMessage      `json:"help,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           sarifRuleProps     `json:"properties"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifRuleProps struct {
+	Validator string `json:"validator,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// writeSARIF сериализует нарушения в SARIF 2.1.0 с метаданными правил
+func writeSARIF(w io.Writer, r *Report) error {
+	driver := sarifDriver{
+		Name:           ToolName,
+		Version:        r.Version,
+		InformationURI: InformationURI,
+		Rules:          []sarifRule{},
+	}
+	ruleIndex := make(map[string]int)
+	results := []sarifResult{}
+
+	for _, finding := range r.Findings {
+		id := finding.RuleID()
+		index, ok := ruleIndex[id]
+		if !ok {
+			index = len(driver.Rules)
+			ruleIndex[id] = index
+			rule := sarifRule{
+				ID:                   id,
+				Name:                 finding.Type,
+				ShortDescription:     sarifMessage{Text: finding.Message},
+				DefaultConfiguration: sarifConfiguration{Level: sarifLevel(finding.Severity)},
+				Properties:           sarifRuleProps{Validator: finding.Validator},
+			}
+			if finding.Suggestion != "" {
+				rule.Help = &sarifMessage{Text: finding.Suggestion}
+			}
+			driver.Rules = append(driver.Rules, rule)
+		}
+
+		location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(finding.Path)}}
+		if finding.Line > 0 {
+			location.Region = &sarifRegion{StartLine: finding.Line, StartColumn: finding.Column}
+		}
+
+		message := finding.Message
+		if finding.Suggestion != "" {
+			message += " " + finding.Suggestion
+		}
+
+		results = append(results, sarifResult{
+			RuleID:    id,
+			RuleIndex: index,
+			Level:     sarifLevel(finding.Severity),
+			Message:   sarifMessage{Text: message},
+			Locations: []sarifLocation{{PhysicalLocation: location}},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
+
+// sarifLevel переводит уровень нарушения в уровень SARIF
+func sarifLevel(level core.Level) string {
+	switch level {
+	case core.LevelCritical, core.LevelError:
+		return "error"
+	case core.LevelWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// JUnit XML: один testcase на файл, критичные нарушения - failure
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit сериализует нарушения в JUnit XML для тестовых отчетов CI
+func writeJUnit(w io.Writer, r *Report) error {
+	files, byFile := groupByFile(r)
+	suite := junitTestSuite{Name: ToolName}
+
+	for _, file := range files {
+		testCase := junitTestCase{ClassName: ToolName, Name: file}
+		var failures, notes []string
+		var first *Finding
+		for i, finding := range byFile[file] {
+			line := fmt.Sprintf("%s:%d:%d: %s: %s", file, finding.Line, finding.Column, finding.RuleID(), finding.Message)
+			if finding.Severity == core.LevelCritical || finding.Severity == core.LevelError {
+				if first == nil {
+					first = &byFile[file][i]
+				}
+				failures = append(failures, line)
+			} else {
+				notes = append(notes, string(finding.Severity)+" "+line)
+			}
+		}
+		if first != nil {
+			testCase.Failure = &junitFailure{
+				Message: first.Message,
+				Type:    first.RuleID(),
+				Text:    strings.Join(failures, "\n"),
+			}
+			suite.Failures++
+		}
+		testCase.SystemOut = strings.Join(notes, "\n")
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	suite.Tests = len(suite.TestCases)
+
+	return writeXML(w, junitTestSuites{
+		Name:     ToolName,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{suite},
+	})
+}
+
+// Checkstyle XML для инструментов ревью кода
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// writeCheckstyle сериализует нарушения в формате checkstyle
+func writeCheckstyle(w io.Writer, r *Report) error {
+	files, byFile := groupByFile(r)
+	out := checkstyleReport{Version: "4.3"}
+
+	for _, file := range files {
+		entry := checkstyleFile{Name: file}
+		for _, finding := range byFile[file] {
+			severity := sarifLevel(finding.Severity)
+			if severity == "note" {
+				severity = "info"
+			}
+			entry.Errors = append(entry.Errors, checkstyleError{
+				Line:     finding.Line,
+				Column:   finding.Column,
+				Severity: severity,
+				Message:  finding.Message,
+				Source:   ToolName + "." + strings.ReplaceAll(finding.RuleID(), "/", "."),
+			})
+		}
+		out.Files = append(out.Files, entry)
+	}
+
+	return writeXML(w, out)
+}
+
+// writeXML записывает XML документ с заголовком
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// writeGitHub печатает workflow команды ::error/::warning/::notice для аннотаций GitHub Actions
+func writeGitHub(w io.Writer, r *Report) error {
+	for _, finding := range r.Findings {
+		command := "notice"
+		switch finding.Severity {
+		case core.LevelCritical, core.LevelError:
+			command = "error"
+		case core.LevelWarning:
+			command = "warning"
+		}
+
+		properties := []string{"file=" + escapeGitHubProperty(filepath.ToSlash(finding.Path))}
+		if finding.Line > 0 {
+			properties = append(properties, fmt.Sprintf("line=%d", finding.Line))
+			if finding.Column > 0 {
+				properties = append(properties, fmt.Sprintf("col=%d", finding.Column))
+			}
+		}
+		properties = append(properties, "title="+escapeGitHubProperty(finding.RuleID()))
+
+		message := finding.Message
+		if finding.Suggestion != "" {
+			message += "\n" + finding.Suggestion
+		}
+
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", command, strings.Join(properties, ","), escapeGitHubData(message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escapeGitHubData экранирует сообщение workflow команды
+func escapeGitHubData(value string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(value)
+}
+
+// escapeGitHubProperty экранирует значение свойства workflow команды
+func escapeGitHubProperty(value string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(value)
+}
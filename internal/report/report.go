@@ -0,0 +1,172 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+)
+
+// Format формат вывода нарушений
+type Format string
+
+const (
+	FormatText       Format = "text"
+	FormatJSON       Format = "json"
+	FormatSARIF      Format = "sarif"
+	FormatJUnit      Format = "junit"
+	FormatCheckstyle Format = "checkstyle"
+	FormatGitHub     Format = "github"
+)
+
+// Formats все поддерживаемые форматы в порядке для справки
+var Formats = []Format{FormatText, FormatJSON, FormatSARIF, FormatJUnit, FormatCheckstyle, FormatGitHub}
+
+// ToolName имя инструмента в отчетах
+const ToolName = "claude-hooks"
+
+// InformationURI страница проекта для SARIF
+const InformationURI = "https://github.com/aiseeq/claude-hooks"
+
+// Finding нарушение с путем к файлу
+type Finding struct {
+	Path string `json:"path"`
+	core.Violation
+}
+
+// RuleID идентификатор правила: валидатор/тип нарушения
+func (f Finding) RuleID() string {
+	if f.Validator == "" {
+		return f.Type
+	}
+	return f.Validator + "/" + f.Type
+}
+
+// Report результат проверки для сериализации
+type Report struct {
+	Version  string    // версия claude-hooks
+	Files    []string  // проверенные файлы, включая файлы без нарушений
+	Findings []Finding // нарушения, упорядоченные по файлу и позиции
+}
+
+// Summary количество нарушений по уровням
+type Summary struct {
+	Files    int `json:"files"`
+	Critical int `json:"critical"`
+	Warnings int `json:"warnings"`
+	Info     int `json:"info"`
+}
+
+// Summary подсчитывает нарушения по уровням
+func (r *Report) Summary() Summary {
+	summary := Summary{Files: len(r.Files)}
+	for _, finding := range r.Findings {
+		switch finding.Severity {
+		case core.LevelCritical, core.LevelError:
+			summary.Critical++
+		case core.LevelWarning:
+			summary.Warnings++
+		default:
+			summary.Info++
+		}
+	}
+	return summary
+}
+
+// ParseFormat проверяет название формата
+func ParseFormat(value string) (Format, error) {
+	for _, format := range Formats {
+		if string(format) == strings.ToLower(value) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q (expected one of: %s)", value, FormatList())
+}
+
+// FormatList возвращает список форматов для справки флага
+func FormatList() string {
+	names := make([]string, len(Formats))
+	for i, format := range Formats {
+		names[i] = string(format)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Write сериализует отчет в выбранном формате, секреты в сообщениях маскируются
+func Write(w io.Writer, format Format, r *Report) error {
+	Sort(r.Findings)
+	for i := range r.Findings {
+		r.Findings[i].Violation = core.RedactViolation(r.Findings[i].Violation)
+	}
+
+	switch format {
+	case FormatText:
+		return writeText(w, r)
+	case FormatJSON:
+		return writeJSON(w, r)
+	case FormatSARIF:
+		return writeSARIF(w, r)
+	case FormatJUnit:
+		return writeJUnit(w, r)
+	case FormatCheckstyle:
+		return writeCheckstyle(w, r)
+	case FormatGitHub:
+		return writeGitHub(w, r)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// Sort упорядочивает нарушения по файлу и позиции
+func Sort(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+// writeText печатает нарушения в формате path:line:col для терминала
+func writeText(w io.Writer, r *Report) error {
+	for _, finding := range r.Findings {
+		fmt.Fprintf(w, "%s:%d:%d: %s %s: %s\n", finding.Path, finding.Line, finding.Column, finding.Severity, finding.RuleID(), finding.Message)
+		if finding.Suggestion != "" {
+			fmt.Fprintf(w, "    💡 %s\n", finding.Suggestion)
+		}
+	}
+
+	summary := r.Summary()
+	_, err := fmt.Fprintf(w, "Scanned %d file(s): %d critical, %d warning(s)\n", summary.Files, summary.Critical, summary.Warnings)
+	return err
+}
+
+// groupByFile группирует нарушения по файлам, сохраняя файлы без нарушений
+func groupByFile(r *Report) ([]string, map[string][]Finding) {
+	byFile := make(map[string][]Finding)
+	var files []string
+	seen := make(map[string]bool)
+
+	for _, file := range r.Files {
+		if !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+	for _, finding := range r.Findings {
+		if !seen[finding.Path] {
+			seen[finding.Path] = true
+			files = append(files, finding.Path)
+		}
+		byFile[finding.Path] = append(byFile[finding.Path], finding)
+	}
+	sort.Strings(files)
+
+	return files, byFile
+}
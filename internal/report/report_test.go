@@ -0,0 +1,105 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+)
+
+func sampleReport() *Report {
+	return &Report{
+		Version: "1.2.3",
+		Files:   []string{"clean.go", "internal/a.go"},
+		Findings: []Finding{
+			{Path: "internal/a.go", Violation: core.Violation{Validator: "secrets", Type: "hardcoded_jwt", Message: "JWT, токен: найден", Line: 7, Column: 3, Severity: core.LevelCritical}},
+			{Path: "internal/a.go", Violation: core.Violation{Validator: "emergency_defaults", Type: "fallback", Message: "100% fallback", Suggestion: "fix", Line: 2, Column: 1, Severity: core.LevelWarning}},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, format := range Formats {
+		if got, err := ParseFormat(strings.ToUpper(string(format))); err != nil || got != format {
+			t.Errorf("format %s should parse, got %q, %v", format, got, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("unknown format should be rejected")
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatSARIF, sampleReport()); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected SARIF envelope: %+v", log)
+	}
+
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || len(run.Results) != 2 {
+		t.Fatalf("expected 2 rules and 2 results, got %d and %d", len(run.Tool.Driver.Rules), len(run.Results))
+	}
+	// Результаты упорядочены по строке: сначала предупреждение со строки 2
+	first := run.Results[0]
+	if first.Level != "warning" || first.RuleID != "emergency_defaults/fallback" {
+		t.Errorf("unexpected first result: %+v", first)
+	}
+	if run.Tool.Driver.Rules[first.RuleIndex].ID != first.RuleID {
+		t.Error("ruleIndex should point to the result rule")
+	}
+	region := run.Results[1].Locations[0].PhysicalLocation.Region
+	if region == nil || region.StartLine != 7 || region.StartColumn != 3 {
+		t.Errorf("unexpected region: %+v", region)
+	}
+}
+
+func TestWriteGitHub_Escaping(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatGitHub, sampleReport()); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 annotations, got %q", buf.String())
+	}
+	if lines[0] != "::warning file=internal/a.go,line=2,col=1,title=emergency_defaults/fallback::100%25 fallback%0Afix" {
+		t.Errorf("unexpected warning annotation: %s", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "::error file=internal/a.go,line=7,col=3,") {
+		t.Errorf("unexpected error annotation: %s", lines[1])
+	}
+}
+
+func TestWriteJUnit_CleanFilesPass(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJUnit, sampleReport()); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	var suites junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("invalid JUnit XML: %v", err)
+	}
+	if suites.Tests != 2 || suites.Failures != 1 {
+		t.Errorf("expected 2 tests and 1 failure, got %d and %d", suites.Tests, suites.Failures)
+	}
+	cases := suites.Suites[0].TestCases
+	if cases[0].Name != "clean.go" || cases[0].Failure != nil {
+		t.Errorf("clean file should pass: %+v", cases[0])
+	}
+	if cases[1].Failure == nil || cases[1].Failure.Type != "secrets/hardcoded_jwt" || cases[1].SystemOut == "" {
+		t.Errorf("critical finding should fail and warning go to system-out: %+v", cases[1])
+	}
+}
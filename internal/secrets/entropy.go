@@ -0,0 +1,201 @@
+// Package secrets находит секреты в тексте: значения с высокой энтропией
+// и токены известных сервисов
+package secrets
+
+import (
+	"math"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// Finding найденный секрет
+type Finding struct {
+	Type   string // тип нарушения, например high_entropy_secret
+	Name   string // имя переменной или ключа, если известно
+	Value  string
+	Offset int // смещение значения в тексте в байтах
+	Line   int
+	Column int
+}
+
+// EntropyOptions настройки поиска по энтропии, нулевые значения заменяются значениями по умолчанию
+type EntropyOptions struct {
+	HexThreshold    float64  // бит на символ для значений из hex символов
+	Base64Threshold float64  // бит на символ для остальных значений
+	MinLength       int      // минимальная длина значения
+	Names           []string // шаблоны имен переменных и ключей (без учета регистра)
+}
+
+// DefaultEntropyOptions настройки по умолчанию
+// Ключи проверяются только с явным назначением: primary_key, cache_key и sort_key секретов не содержат
+func DefaultEntropyOptions() EntropyOptions {
+	return EntropyOptions{
+		HexThreshold:    3.0,
+		Base64Threshold: 4.0,
+		MinLength:       16,
+		Names: []string{
+			"*token*", "*secret*", "*password*", "*passwd*", "*pwd",
+			"*{api,access,private,signing,encryption,master,auth}{_,-}key", "*apikey*", "*accesskey*", "*privatekey*",
+			"*credential*", "auth", "*_auth",
+		},
+	}
+}
+
+// EntropyTypeSecret тип нарушения для значения с высокой энтропией
+const EntropyTypeSecret = "high_entropy_secret"
+
+// lockFiles файлы зависимостей, где хэши - норма
+var lockFiles = map[string]bool{
+	"go.sum": true, "go.work.sum": true,
+	"package-lock.json": true, "npm-shrinkwrap.json": true, "yarn.lock": true, "pnpm-lock.yaml": true, "bun.lock": true,
+	"Cargo.lock": true, "poetry.lock": true, "Pipfile.lock": true, "uv.lock": true,
+	"Gemfile.lock": true, "composer.lock": true,
+}
+
+var (
+	// assignmentPattern имя и значение: name = "v", name: v, "name": "v", name := `v`, name => 'v'
+	assignmentPattern = regexp.MustCompile("([A-Za-z_][A-Za-z0-9_.\\-]*)[\"'`]?\\s*(?::=|=>|={1,3}|:)\\s*(?:\"([^\"\\n]*)\"|'([^'\\n]*)'|`([^`\\n]*)`|([^\\s\"'`#,;)}\\]]+))")
+
+	uuidPattern = regexp.MustCompile(`^(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	hexPattern  = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+	// identifierPattern имена, пути и константы без цифр
+	identifierPattern = regexp.MustCompile(`^[A-Za-z_.\-/]+$`)
+)
+
+// metadataSuffixes окончания имен, описывающих секрет, а не хранящих его: tokenPattern, passwordFormat
+var metadataSuffixes = []string{"pattern", "regex", "regexp", "format", "template", "header", "field", "prefix"}
+
+// digestPrefixes префиксы хэшей и дайджестов (npm integrity, go.sum, docker)
+var digestPrefixes = []string{"sha1-", "sha256-", "sha384-", "sha512-", "sha256:", "sha512:", "h1:"}
+
+// ShannonEntropy возвращает энтропию Шеннона строки в битах на символ
+func ShannonEntropy(value string) float64 {
+	if value == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range value {
+		counts[r]++
+		total++
+	}
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// EntropyDetector ищет значения с высокой энтропией в присваиваниях секретных имен
+type EntropyDetector struct {
+	options EntropyOptions
+}
+
+// NewEntropyDetector создает детектор, незаданные настройки берутся по умолчанию
+func NewEntropyDetector(options EntropyOptions) *EntropyDetector {
+	defaults := DefaultEntropyOptions()
+	if options.HexThreshold <= 0 {
+		options.HexThreshold = defaults.HexThreshold
+	}
+	if options.Base64Threshold <= 0 {
+		options.Base64Threshold = defaults.Base64Threshold
+	}
+	if options.MinLength <= 0 {
+		options.MinLength = defaults.MinLength
+	}
+	if len(options.Names) == 0 {
+		options.Names = defaults.Names
+	}
+	return &EntropyDetector{options: options}
+}
+
+// Find ищет секреты в содержимом файла
+// Файлы зависимостей (go.sum, package-lock.json и т.п.) не проверяются
+func (d *EntropyDetector) Find(filePath, content string) []Finding {
+	if lockFiles[filepath.Base(filePath)] {
+		return nil
+	}
+
+	var findings []Finding
+	lineStart := 0
+	for lineNum, line := range strings.Split(content, "\n") {
+		for _, match := range assignmentPattern.FindAllStringSubmatchIndex(line, -1) {
+			name := line[match[2]:match[3]]
+			start, end, group := -1, -1, 0
+			for group = 2; group <= 5; group++ {
+				if match[2*group] >= 0 {
+					start, end = match[2*group], match[2*group+1]
+					break
+				}
+			}
+			if start < 0 {
+				continue
+			}
+			value := line[start:end]
+			// Значение без кавычек со скобкой - вызов функции, а не литерал
+			if group == 5 && strings.ContainsRune(value, '(') {
+				continue
+			}
+			if !d.secretName(name) || !d.suspicious(value) {
+				continue
+			}
+			findings = append(findings, Finding{
+				Type:   EntropyTypeSecret,
+				Name:   name,
+				Value:  value,
+				Offset: lineStart + start,
+				Line:   lineNum + 1,
+				Column: start + 1,
+			})
+		}
+		lineStart += len(line) + 1
+	}
+
+	return findings
+}
+
+// secretName проверяет имя переменной или ключа по шаблонам, имена шаблонов и форматов не считаются секретными
+// Для составных имен (config.api_key) проверяется последний сегмент
+func (d *EntropyDetector) secretName(name string) bool {
+	name = strings.ToLower(name)
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		name = name[dot+1:]
+	}
+	for _, suffix := range metadataSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return false
+		}
+	}
+	for _, pattern := range d.options.Names {
+		if ok, _ := doublestar.Match(strings.ToLower(pattern), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// suspicious проверяет похоже ли значение на секрет
+// Подстановки (${VAR}, {{ .Values.x }}, <password>) и заглушки пропускаются, спецсимволы в пароле - нет
+func (d *EntropyDetector) suspicious(value string) bool {
+	if len(value) < d.options.MinLength || AllowedValue(value) {
+		return false
+	}
+	if uuidPattern.MatchString(value) || identifierPattern.MatchString(value) || strings.Contains(value, "://") {
+		return false
+	}
+	for _, prefix := range digestPrefixes {
+		if strings.HasPrefix(value, prefix) {
+			return false
+		}
+	}
+
+	threshold := d.options.Base64Threshold
+	if hexPattern.MatchString(value) {
+		threshold = d.options.HexThreshold
+	}
+	return ShannonEntropy(value) >= threshold
+}
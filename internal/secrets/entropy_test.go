@@ -0,0 +1,100 @@
+package secrets
+
+import (
+	"math"
+	"testing"
+)
+
+func TestShannonEntropy(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+	}{
+		{"", 0},
+		{"aaaa", 0},
+		{"abab", 1},
+		{"abcd", 2},
+		{"0123456789abcdef", 4},
+	}
+
+	for _, tt := range tests {
+		if got := ShannonEntropy(tt.value); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("ShannonEntropy(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestEntropyDetector_Find(t *testing.T) {
+	detector := NewEntropyDetector(EntropyOptions{})
+
+	tests := []struct {
+		name     string
+		path     string
+		content  string
+		wantName string
+		wantLine int
+		wantCol  int
+	}{
+		{"go assignment", "config.go", `apiToken := "q8Zr2LmX4vT9nB7cK1pW"`, "apiToken", 1, 14},
+		{"python password", "settings.py", "\nDB_PASSWORD = 'hT4#kP9xQ2mZ7wLs'", "DB_PASSWORD", 2, 16},
+		{"json key", "creds.json", `{"client_secret": "Xy7Kp2Lq9Rz4Tm8Vn3Ws"}`, "client_secret", 1, 20},
+		{"yaml unquoted", "app.yaml", "auth:\n  signing_key: 9f86d081884c7d659a2feaa0c55ad015", "signing_key", 2, 16},
+		{"env file", ".env", "STRIPE_SECRET=Zk3Lm9Qp2Xr7Tn4Wv8Ys", "STRIPE_SECRET", 1, 15},
+		{"dotted name", "config.ts", `config.github_token = "a9B3c7D1e5F2g8H4i6J0"`, "config.github_token", 1, 24},
+		{"non secret name", "config.go", `requestID := "q8Zr2LmX4vT9nB7cK1pW"`, "", 0, 0},
+		{"api key", "config.py", `openai_api_key = "q8Zr2LmX4vT9nB7cK1pW"`, "openai_api_key", 1, 19},
+		{"primary key", "schema.yaml", "primary_key: Xk9mP2vL8qR4wZ7tB3nC", "", 0, 0},
+		{"cache key", "cache.go", `cache_key := "user:42:Xk9mP2vL8qR4wZ7t"`, "", 0, 0},
+		{"sort key", "table.ts", `const sort_key = "Xk9mP2vL8qR4wZ7tB3nC"`, "", 0, 0},
+		{"partition key", "table.json", `{"partition-key": "Xk9mP2vL8qR4wZ7tB3nC"}`, "", 0, 0},
+		{"low entropy", "config.go", `password := "aaaaaaaaaaaaaaaaaaaa"`, "", 0, 0},
+		{"too short", "config.go", `token := "q8Zr2LmX"`, "", 0, 0},
+		{"uuid", "config.go", `token := "123e4567-e89b-12d3-a456-426614174000"`, "", 0, 0},
+		{"env lookup", "config.go", `token := os.Getenv("API_TOKEN_VALUE_FROM_ENV")`, "", 0, 0},
+		{"identifier", "config.ts", `const tokenKind = "ACCESS_TOKEN_REFRESH_KIND"`, "", 0, 0},
+		{"password with special characters", "config.ts", `const dbPassword = "Xk9#mP2$vL8qR4wZ"`, "dbPassword", 1, 21},
+		{"password with braces", "config.py", `db_password = "q8(Zr2}LmX<4vT9%"`, "db_password", 1, 16},
+		{"placeholder", "app.yaml", "api_key: ${API_KEY_FROM_ENVIRONMENT}", "", 0, 0},
+		{"template", "values.yaml", "secret_token: \"{{ .Values.apiSecretToken }}\"", "", 0, 0},
+		{"regex literal", "secrets.go", `apiKeyPattern := "(sk_|pk_|api_key_)[a-zA-Z0-9]{20,}"`, "", 0, 0},
+		{"unquoted call", "config.py", "secret_key = load_secret_from_vault(settings.vault_path)", "", 0, 0},
+		{"npm integrity", "deps.json", `"integrity_token": "sha512-q8Zr2LmX4vT9nB7cK1pWq8Zr2LmX4vT9nB7cK1pW"`, "", 0, 0},
+		{"go.sum", "go.sum", "secret_key: 9f86d081884c7d659a2feaa0c55ad015", "", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := detector.Find(tt.path, tt.content)
+			if tt.wantName == "" {
+				if len(findings) != 0 {
+					t.Fatalf("expected no findings, got %+v", findings)
+				}
+				return
+			}
+			if len(findings) != 1 {
+				t.Fatalf("expected one finding, got %+v", findings)
+			}
+			f := findings[0]
+			if f.Name != tt.wantName || f.Line != tt.wantLine || f.Column != tt.wantCol || f.Type != EntropyTypeSecret {
+				t.Errorf("got %+v, want name %s at %d:%d", f, tt.wantName, tt.wantLine, tt.wantCol)
+			}
+			if tt.content[f.Offset:f.Offset+len(f.Value)] != f.Value {
+				t.Errorf("offset %d does not point at value %q", f.Offset, f.Value)
+			}
+		})
+	}
+}
+
+func TestEntropyDetector_Options(t *testing.T) {
+	content := `session_id = "q8Zr2LmX4vT9nB7cK1pW"`
+
+	if findings := NewEntropyDetector(EntropyOptions{}).Find("a.py", content); len(findings) != 0 {
+		t.Fatalf("default names should not match session_id: %+v", findings)
+	}
+	if findings := NewEntropyDetector(EntropyOptions{Names: []string{"SESSION_*"}}).Find("a.py", content); len(findings) != 1 {
+		t.Fatalf("configured names should match case-insensitively: %+v", findings)
+	}
+	if findings := NewEntropyDetector(EntropyOptions{Names: []string{"session_*"}, Base64Threshold: 5}).Find("a.py", content); len(findings) != 0 {
+		t.Fatalf("higher threshold should skip the value: %+v", findings)
+	}
+}
@@ -0,0 +1,22 @@
+package secrets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Fingerprint возвращает SHA-256 отпечаток значения в формате allowlist: sha256:<hex>
+func Fingerprint(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Fingerprint возвращает отпечаток найденного значения или "", если значение не отличает
+// один секрет от другого: заголовок PEM без тела одинаков у всех ключей и в allowlist не попадает
+func (f Finding) Fingerprint() string {
+	if f.Type == RulePrivateKey && !strings.Contains(f.Value, "-----END ") {
+		return ""
+	}
+	return Fingerprint(f.Value)
+}
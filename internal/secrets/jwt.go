@@ -0,0 +1,94 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Типы нарушений для JWT токенов по классификации
+const (
+	JWTTypeExpired    = "expired_jwt"     // срок действия (exp) истек
+	JWTTypeTestIssuer = "test_issuer_jwt" // издатель из списка тестовых
+)
+
+// JWT разобранный JWS токен: заголовок и утверждения без проверки подписи
+type JWT struct {
+	Algorithm string    // alg заголовка
+	Issuer    string    // iss, пусто если не задан
+	Expires   time.Time // exp, нулевое время если не задан
+	Unsigned  bool      // alg: none или пустая подпись
+}
+
+// ParseJWT разбирает токен header.claims.signature
+// Строка, которая только начинается как base64 JSON, но не является JWS токеном, не разбирается
+func ParseJWT(token string) (*JWT, bool) {
+	segments := strings.Split(token, ".")
+	// Точка после подписанного токена - конец предложения, а не четвертый сегмент
+	if len(segments) == 4 && segments[3] == "" && segments[2] != "" {
+		segments = segments[:3]
+	}
+	if len(segments) != 3 || segments[0] == "" || segments[1] == "" {
+		return nil, false
+	}
+
+	var header struct {
+		Algorithm *string `json:"alg"`
+	}
+	if !decodeSegment(segments[0], &header) || header.Algorithm == nil {
+		return nil, false
+	}
+
+	var claims struct {
+		Issuer  any `json:"iss"`
+		Expires any `json:"exp"`
+	}
+	if !decodeSegment(segments[1], &claims) {
+		return nil, false
+	}
+
+	jwt := &JWT{
+		Algorithm: *header.Algorithm,
+		Unsigned:  strings.EqualFold(*header.Algorithm, "none") || segments[2] == "",
+	}
+	if issuer, ok := claims.Issuer.(string); ok {
+		jwt.Issuer = issuer
+	}
+	if exp, ok := claims.Expires.(float64); ok {
+		jwt.Expires = time.Unix(int64(exp), 0).UTC()
+	}
+	return jwt, true
+}
+
+// decodeSegment декодирует base64url сегмент токена в JSON объект
+func decodeSegment(segment string, value any) bool {
+	segment = strings.TrimRight(segment, "=")
+	segment = strings.NewReplacer("+", "-", "/", "_").Replace(segment)
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, value) == nil && strings.HasPrefix(strings.TrimSpace(string(data)), "{")
+}
+
+// Expired проверяет, истек ли срок действия токена на момент now
+func (t *JWT) Expired(now time.Time) bool {
+	return !t.Expires.IsZero() && !now.Before(t.Expires)
+}
+
+// Summary описание токена для сообщения: алгоритм, издатель, срок действия и отсутствие подписи
+func (t *JWT) Summary() string {
+	parts := []string{"alg: " + t.Algorithm}
+	if t.Issuer != "" {
+		parts = append(parts, "iss: "+t.Issuer)
+	}
+	if !t.Expires.IsZero() {
+		parts = append(parts, "exp: "+t.Expires.Format(time.RFC3339))
+	}
+	if t.Unsigned {
+		parts = append(parts, "без подписи")
+	}
+	return fmt.Sprintf("(%s)", strings.Join(parts, ", "))
+}
@@ -0,0 +1,70 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+// makeJWT собирает токен из JSON заголовка и утверждений
+func makeJWT(header, claims, signature string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(header)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(claims)) + "." + signature
+}
+
+func TestParseJWT(t *testing.T) {
+	tests := []struct {
+		name         string
+		token        string
+		wantOK       bool
+		wantAlg      string
+		wantIssuer   string
+		wantExpires  int64
+		wantUnsigned bool
+	}{
+		{"signed", makeJWT(`{"alg":"HS256","typ":"JWT"}`, `{"iss":"auth.example.com","exp":1700000000}`, "c2ln"), true, "HS256", "auth.example.com", 1700000000, false},
+		{"unsigned", makeJWT(`{"alg":"none"}`, `{"sub":"1"}`, ""), true, "none", "", 0, true},
+		{"trailing period", makeJWT(`{"alg":"RS256"}`, `{}`, "c2ln") + ".", true, "RS256", "", 0, false},
+		{"two segments", strings.TrimSuffix(makeJWT(`{"alg":"HS256"}`, `{}`, ""), "."), false, "", "", 0, false},
+		{"base64 json without alg", makeJWT(`{"name":"x"}`, `{}`, "c2ln"), false, "", "", 0, false},
+		{"claims are not an object", makeJWT(`{"alg":"HS256"}`, `"text"`, "c2ln"), false, "", "", 0, false},
+		{"not base64", "eyJhbGciOiJIUzI1NiJ9.@@@.c2ln", false, "", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jwt, ok := ParseJWT(tt.token)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseJWT(%q) ok = %v, want %v", tt.token, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if jwt.Algorithm != tt.wantAlg || jwt.Issuer != tt.wantIssuer || jwt.Unsigned != tt.wantUnsigned {
+				t.Errorf("ParseJWT() = %+v", jwt)
+			}
+			if tt.wantExpires != 0 && jwt.Expires.Unix() != tt.wantExpires {
+				t.Errorf("Expires = %v, want %d", jwt.Expires, tt.wantExpires)
+			}
+		})
+	}
+}
+
+func TestJWT_ExpiredAndSummary(t *testing.T) {
+	jwt, ok := ParseJWT(makeJWT(`{"alg":"none"}`, `{"iss":"test-idp","exp":1700000000}`, ""))
+	if !ok {
+		t.Fatal("token should parse")
+	}
+	if !jwt.Expired(time.Unix(1700000000, 0)) || jwt.Expired(time.Unix(1699999999, 0)) {
+		t.Error("token should expire exactly at exp")
+	}
+	if want := "(alg: none, iss: test-idp, exp: 2023-11-14T22:13:20Z, без подписи)"; jwt.Summary() != want {
+		t.Errorf("Summary() = %q, want %q", jwt.Summary(), want)
+	}
+
+	noExpiry, _ := ParseJWT(makeJWT(`{"alg":"HS256"}`, `{}`, "c2ln"))
+	if noExpiry.Expired(time.Now()) {
+		t.Error("token without exp never expires")
+	}
+}
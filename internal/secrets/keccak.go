@@ -0,0 +1,85 @@
+package secrets
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// keccakRate размер блока Keccak-256 в байтах
+const keccakRate = 136
+
+var keccakRoundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808A, 0x8000000080008000,
+	0x000000000000808B, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008A, 0x0000000000000088, 0x0000000080008009, 0x000000008000000A,
+	0x000000008000808B, 0x800000000000008B, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800A, 0x800000008000000A,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// keccakRotations и keccakLanes задают шаги rho и pi перестановки
+var (
+	keccakRotations = [24]int{1, 3, 6, 10, 15, 21, 28, 36, 45, 55, 2, 14, 27, 41, 56, 8, 25, 43, 62, 18, 39, 61, 20, 44}
+	keccakLanes     = [24]int{10, 7, 11, 17, 18, 3, 5, 16, 8, 21, 24, 4, 15, 23, 19, 13, 12, 2, 20, 14, 22, 9, 6, 1}
+)
+
+// keccak256 хэш Keccak-256 (вариант до стандартизации SHA-3), на нем основана контрольная сумма EIP-55
+// Стандартная библиотека содержит только SHA-3 с другим дополнением, поэтому перестановка реализована здесь
+func keccak256(data []byte) []byte {
+	// Дополнение Keccak: 0x01 ... 0x80 до кратного размеру блока
+	message := make([]byte, len(data), len(data)+keccakRate)
+	copy(message, data)
+	message = append(message, 0x01)
+	for len(message)%keccakRate != 0 {
+		message = append(message, 0)
+	}
+	message[len(message)-1] |= 0x80
+
+	var state [25]uint64
+	for block := 0; block < len(message); block += keccakRate {
+		for i := 0; i < keccakRate/8; i++ {
+			state[i] ^= binary.LittleEndian.Uint64(message[block+8*i:])
+		}
+		keccakF1600(&state)
+	}
+
+	digest := make([]byte, 32)
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint64(digest[8*i:], state[i])
+	}
+	return digest
+}
+
+// keccakF1600 перестановка Keccak-f[1600]
+func keccakF1600(a *[25]uint64) {
+	var c [5]uint64
+	for round := 0; round < 24; round++ {
+		// theta
+		for x := 0; x < 5; x++ {
+			c[x] = a[x] ^ a[x+5] ^ a[x+10] ^ a[x+15] ^ a[x+20]
+		}
+		for x := 0; x < 5; x++ {
+			d := c[(x+4)%5] ^ bits.RotateLeft64(c[(x+1)%5], 1)
+			for y := 0; y < 25; y += 5 {
+				a[y+x] ^= d
+			}
+		}
+
+		// rho и pi
+		t := a[1]
+		for i, lane := range keccakLanes {
+			t, a[lane] = a[lane], bits.RotateLeft64(t, keccakRotations[i])
+		}
+
+		// chi
+		for y := 0; y < 25; y += 5 {
+			copy(c[:], a[y:y+5])
+			for x := 0; x < 5; x++ {
+				a[y+x] = c[x] ^ (^c[(x+1)%5] & c[(x+2)%5])
+			}
+		}
+
+		// iota
+		a[0] ^= keccakRoundConstants[round]
+	}
+}
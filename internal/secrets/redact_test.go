@@ -0,0 +1,73 @@
+package secrets
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestMask(t *testing.T) {
+	if got := Mask("short"); got != "****" {
+		t.Errorf("Mask(short) = %q", got)
+	}
+	if got := Mask(githubToken); got != "ghp_****e5F6" {
+		t.Errorf("Mask(token) = %q", got)
+	}
+
+	command := "export DB_PASSWORD=prod-pass-123 && mysql -pS3cret"
+	findings := NewCommandScanner(nil, nil).Find(command)
+	if got := MaskFindings(command, findings); got != "export DB_PASSWORD=**** && mysql -p****" {
+		t.Errorf("MaskFindings = %q", got)
+	}
+}
+
+func TestRedactor_Redact(t *testing.T) {
+	jwt := "eyJ" + "hbGciOiJIUzI1NiJ9." + "eyJ" + "zdWIiOiIxMjM0NTY3ODkwIn0.c2lnbmF0dXJl"
+	tests := []struct {
+		name   string
+		text   string
+		want   string
+		secret string
+	}{
+		{"vendor token", "push failed for " + githubToken, "push failed for ghp_****e5F6", githubToken},
+		{"bash command", "mysql -u root -pS3cret app", "mysql -u root -p**** app", "S3cret"},
+		{"env assignment", "GITHUB_TOKEN=" + githubToken + " gh pr list", "GITHUB_TOKEN=ghp_****e5F6 gh pr list", githubToken},
+		{"jwt with payload", "token " + jwt, "token eyJh****dXJl", jwt},
+		{"high entropy assignment", `api_key = "Zk3pQ9xL2mVb7RtY8wNc"`, `api_key = "Zk3p****8wNc"`, "Zk3pQ9xL2mVb7RtY8wNc"},
+		{"plain text", "hook blocked operation on main.go", "hook blocked operation on main.go", ""},
+		{"already masked", "Credential in bash command: Authorization: eyJh****ijkl", "Credential in bash command: Authorization: eyJh****ijkl", ""},
+		{"masked and plain", "ghp_****e5F6 and " + githubToken, "ghp_****e5F6 and ghp_****e5F6", githubToken},
+	}
+
+	redactor := NewRedactor(DefaultRedactorOptions())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redactor.Redact(tt.text)
+			if tt.secret != "" && strings.Contains(got, tt.secret) {
+				t.Fatalf("Redact(%q) = %q, secret leaked", tt.text, got)
+			}
+			if got != tt.want {
+				t.Errorf("Redact(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactor_Options(t *testing.T) {
+	jwt := "eyJ" + "hbGciOiJIUzI1NiJ9." + "eyJ" + "zdWIiOiIxMjM0NTY3ODkwIn0.c2lnbmF0dXJl"
+	text := "push failed for " + githubToken + " session_cookie=Zk3pQ9xL2mVb7RtY8wNc token " + jwt
+
+	redactor := NewRedactor(RedactorOptions{
+		RuleEnabled: func(id string) bool { return id != RuleGitHubToken },
+		JWTPattern:  regexp.MustCompile(`\bnever-matches\b`),
+		Entropy:     NewEntropyDetector(EntropyOptions{Names: []string{"*cookie*"}}),
+	})
+	got := redactor.Redact(text)
+
+	if strings.Contains(got, "Zk3pQ9xL2mVb7RtY8wNc") {
+		t.Errorf("configured entropy names should be masked: %q", got)
+	}
+	if !strings.Contains(got, githubToken) || !strings.Contains(got, jwt) {
+		t.Errorf("disabled rule and custom JWT pattern should leave values as is: %q", got)
+	}
+}
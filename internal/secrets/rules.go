@@ -0,0 +1,236 @@
+package secrets
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Rule правило поиска токена известного сервиса
+// ID используется как тип нарушения и как ключ в rules конфигурации валидатора
+type Rule struct {
+	ID         string
+	Message    string
+	Suggestion string
+	pattern    *regexp.Regexp
+	group      int               // номер группы со значением секрета, 0 - все совпадение
+	valid      func(string) bool // дополнительная проверка значения, nil - любое
+}
+
+// Типы нарушений правил известных сервисов
+const (
+	RuleAWSAccessKey       = "aws_access_key"
+	RuleAWSSecretKey       = "aws_secret_key"
+	RuleGitHubToken        = "github_token"
+	RuleSlackToken         = "slack_token"
+	RuleStripeLiveKey      = "stripe_live_key"
+	RuleGoogleAPIKey       = "google_api_key"
+	RuleAnthropicAPIKey    = "anthropic_api_key"
+	RuleOpenAIAPIKey       = "openai_api_key"
+	RulePrivateKey         = "private_key"
+	RuleConnectionPassword = "connection_string_password"
+)
+
+// awsSecretPattern секретный ключ AWS: 40 символов base64 без '='
+var awsSecretPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9/+])([A-Za-z0-9/+]{40})(?:[^A-Za-z0-9/+=]|$)`)
+
+// awsPairLines сколько строк вокруг идентификатора ключа AWS проверяется на секретный ключ
+const awsPairLines = 3
+
+// VendorRules встроенные правила известных сервисов
+var VendorRules = []Rule{
+	{
+		ID:         RuleAWSAccessKey,
+		Message:    "Обнаружен идентификатор ключа доступа AWS",
+		Suggestion: "Используй IAM роли или профили AWS вместо ключей в коде; скомпрометированный ключ деактивируй в IAM",
+		pattern:    regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	},
+	{
+		ID:         RuleAWSSecretKey,
+		Message:    "Обнаружен секретный ключ доступа AWS",
+		Suggestion: "Используй IAM роли или профили AWS вместо ключей в коде; скомпрометированный ключ деактивируй в IAM",
+		pattern:    regexp.MustCompile(`(?i)aws_?secret_?(?:access_?)?key["'` + "`" + `]?\s*(?::=|=>|=|:)\s*["'` + "`" + `]?([A-Za-z0-9/+]{40})(?:[^A-Za-z0-9/+=]|$)`),
+		group:      1,
+	},
+	{
+		ID:         RuleGitHubToken,
+		Message:    "Обнаружен токен GitHub",
+		Suggestion: "Отзови токен в настройках GitHub и передавай новый через переменную окружения (GITHUB_TOKEN)",
+		pattern:    regexp.MustCompile(`\b(?:(?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9]{22}_[A-Za-z0-9]{59})\b`),
+	},
+	{
+		ID:         RuleSlackToken,
+		Message:    "Обнаружен токен Slack",
+		Suggestion: "Отзови токен в настройках приложения Slack и передавай новый через переменную окружения",
+		pattern:    regexp.MustCompile(`\bxox[abposr]-[0-9]+-[A-Za-z0-9-]{10,}`),
+	},
+	{
+		ID:         RuleStripeLiveKey,
+		Message:    "Обнаружен боевой ключ Stripe",
+		Suggestion: "Перевыпусти ключ в панели Stripe; в коде и тестах используй sk_test_ ключи из переменных окружения",
+		pattern:    regexp.MustCompile(`\b(?:sk|rk)_live_[A-Za-z0-9]{24,}\b`),
+	},
+	{
+		ID:         RuleGoogleAPIKey,
+		Message:    "Обнаружен API ключ Google",
+		Suggestion: "Перевыпусти ключ в Google Cloud Console и ограничь его по API и источникам запросов",
+		pattern:    regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`),
+	},
+	{
+		ID:         RuleAnthropicAPIKey,
+		Message:    "Обнаружен API ключ Anthropic",
+		Suggestion: "Отзови ключ в Anthropic Console и передавай новый через ANTHROPIC_API_KEY",
+		pattern:    regexp.MustCompile(`\bsk-ant-[a-z]+[0-9]*-[A-Za-z0-9_\-]{32,}`),
+	},
+	{
+		ID:         RuleOpenAIAPIKey,
+		Message:    "Обнаружен API ключ OpenAI",
+		Suggestion: "Отзови ключ в настройках OpenAI и передавай новый через OPENAI_API_KEY",
+		pattern:    regexp.MustCompile(`\bsk-(?:(?:proj|svcacct|admin)-[A-Za-z0-9_\-]{40,}|[A-Za-z0-9]{20}T3BlbkFJ[A-Za-z0-9]{20}\b)`),
+	},
+	{
+		ID:         RulePrivateKey,
+		Message:    "Обнаружен приватный ключ в формате PEM",
+		Suggestion: "Храни ключи вне репозитория (секрет-менеджер, монтируемый файл) и перевыпусти скомпрометированный ключ",
+		// Значение - весь блок до END, чтобы отпечатки разных ключей различались; тело не содержит "--"
+		pattern: regexp.MustCompile(`-----BEGIN (?:(?:RSA|DSA|EC|OPENSSH|PGP|ENCRYPTED) )?PRIVATE KEY(?: BLOCK)?-----` +
+			`(?:(?:[^-]|-[^-])*-----END (?:(?:RSA|DSA|EC|OPENSSH|PGP|ENCRYPTED) )?PRIVATE KEY(?: BLOCK)?-----)?`),
+	},
+	{
+		ID:         RuleConnectionPassword,
+		Message:    "Обнаружен пароль в строке подключения",
+		Suggestion: "Собирай строку подключения из переменных окружения или используй отдельную переменную для пароля",
+		pattern:    regexp.MustCompile(`\b[A-Za-z][A-Za-z0-9+.\-]*://[^:/?#@\s"'` + "`" + `]+:([^@/?#\s"'` + "`" + `]+)@[^\s"'` + "`" + `]`),
+		group:      1,
+		valid:      realPassword,
+	},
+}
+
+// placeholderPasswords типовые заглушки паролей в примерах строк подключения
+var placeholderPasswords = map[string]bool{
+	"password": true, "passwd": true, "pass": true, "pwd": true, "secret": true,
+	"changeme": true, "example": true, "user": true, "username": true, "test": true,
+}
+
+// realPassword отсекает заглушки и подстановки: ${DB_PASSWORD}, <password>, ***, %s
+func realPassword(value string) bool {
+	if strings.ContainsAny(value, "${}<>%*") {
+		return false
+	}
+	if placeholderPasswords[strings.ToLower(value)] {
+		return false
+	}
+	return strings.Trim(value, value[:1]) != ""
+}
+
+// LookupRule возвращает встроенное правило по идентификатору
+func LookupRule(id string) (Rule, bool) {
+	for _, rule := range VendorRules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// RuleDetector ищет токены известных сервисов по включенным правилам
+type RuleDetector struct {
+	rules []Rule
+}
+
+// NewRuleDetector создает детектор; enabled решает, включено ли правило, nil - все правила
+func NewRuleDetector(enabled func(id string) bool) *RuleDetector {
+	detector := &RuleDetector{}
+	for _, rule := range VendorRules {
+		if enabled == nil || enabled(rule.ID) {
+			detector.rules = append(detector.rules, rule)
+		}
+	}
+	return detector
+}
+
+// Find ищет токены известных сервисов в тексте
+// Секретный ключ AWS без имени находится только рядом с идентификатором ключа
+func (d *RuleDetector) Find(content string) []Finding {
+	var findings []Finding
+	seen := make(map[int]bool)
+	pairAWS := false
+
+	for _, rule := range d.rules {
+		if rule.ID == RuleAWSSecretKey {
+			pairAWS = true
+		}
+		for _, match := range rule.pattern.FindAllStringSubmatchIndex(content, -1) {
+			start, end := match[2*rule.group], match[2*rule.group+1]
+			value := content[start:end]
+			if seen[start] || (rule.valid != nil && !rule.valid(value)) {
+				continue
+			}
+			seen[start] = true
+			findings = append(findings, newFinding(rule.ID, content, value, start))
+		}
+	}
+
+	if pairAWS {
+		for _, finding := range findings {
+			if finding.Type == RuleAWSAccessKey {
+				findings = append(findings, pairedAWSSecrets(content, finding, seen)...)
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Offset < findings[j].Offset })
+	return findings
+}
+
+// pairedAWSSecrets ищет секретный ключ в строках рядом с идентификатором ключа AWS
+func pairedAWSSecrets(content string, accessKey Finding, seen map[int]bool) []Finding {
+	lines := strings.Split(content, "\n")
+	from := accessKey.Line - 1 - awsPairLines
+	if from < 0 {
+		from = 0
+	}
+	to := accessKey.Line - 1 + awsPairLines
+	if to >= len(lines) {
+		to = len(lines) - 1
+	}
+
+	offset := 0
+	for i := 0; i < from; i++ {
+		offset += len(lines[i]) + 1
+	}
+
+	var findings []Finding
+	for i := from; i <= to; i++ {
+		for _, match := range awsSecretPattern.FindAllStringSubmatchIndex(lines[i], -1) {
+			start := offset + match[2]
+			value := lines[i][match[2]:match[3]]
+			if seen[start] || !mixedCase(value) {
+				continue
+			}
+			seen[start] = true
+			findings = append(findings, newFinding(RuleAWSSecretKey, content, value, start))
+		}
+		offset += len(lines[i]) + 1
+	}
+	return findings
+}
+
+// mixedCase проверяет наличие цифр и букв обоих регистров, отсекая идентификаторы и хэши
+func mixedCase(value string) bool {
+	return strings.ContainsAny(value, "0123456789") &&
+		strings.ContainsAny(value, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") &&
+		strings.ContainsAny(value, "abcdefghijklmnopqrstuvwxyz")
+}
+
+// newFinding создает находку с позицией значения в тексте
+func newFinding(ruleType, content, value string, offset int) Finding {
+	lineStart := strings.LastIndexByte(content[:offset], '\n') + 1
+	return Finding{
+		Type:   ruleType,
+		Value:  value,
+		Offset: offset,
+		Line:   strings.Count(content[:offset], "\n") + 1,
+		Column: offset - lineStart + 1,
+	}
+}
@@ -0,0 +1,418 @@
+package secrets
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"gopkg.in/yaml.v3"
+)
+
+// StructuredTypeSecret тип нарушения для значения чувствительного ключа в конфигурационном файле
+const StructuredTypeSecret = "sensitive_config_value"
+
+// Форматы конфигурационных файлов
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+	FormatEnv  = "env"
+)
+
+// DefaultSensitiveKeys шаблоны чувствительных ключей (без учета регистра, по последнему сегменту пути)
+// token только в конце ключа: tokenizer и token_count секретов не содержат
+var DefaultSensitiveKeys = []string{
+	"*password*", "*passwd*", "*pwd", "*secret*", "*token", "*tokens",
+	"*api_key*", "*api-key*", "*apikey*", "*private_key*", "*privatekey*",
+	"*access_key*", "*accesskey*", "*credential*", "*auth_key*", "*authkey*",
+}
+
+// referenceSuffixes окончания ключей, значения которых указывают на секрет, а не содержат его:
+// password_file, token_url, secret_name, DB_PASSWORD_ENV
+var referenceSuffixes = []string{"file", "path", "env", "var", "url", "name", "type", "length", "policy", "ttl", "stdin", "prompt"}
+
+// quantityWords слова ключей с количеством, а не секретом: max_tokens, num_tokens, token_count
+var quantityWords = map[string]bool{"max": true, "min": true, "num": true, "total": true, "count": true, "limit": true, "budget": true, "usage": true}
+
+// vaultPrefixes ссылки на секрет-менеджеры вместо значения
+var vaultPrefixes = []string{
+	"vault:", "ref+", "secret://", "op://", "sm://", "ssm:", "secretsmanager:",
+	"arn:aws:secretsmanager:", "arn:aws:ssm:", "azurekeyvault:", "keyvault:", "gcp-secret:", "env:",
+}
+
+// StructuredFormat определяет формат конфигурационного файла по имени, "" - не конфигурационный файл
+func StructuredFormat(filePath string) string {
+	base := strings.ToLower(filepath.Base(filePath))
+	switch {
+	case base == ".env" || strings.HasPrefix(base, ".env.") || strings.HasSuffix(base, ".env"):
+		return FormatEnv
+	}
+	switch filepath.Ext(base) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	}
+	return ""
+}
+
+// StructuredScanner разбирает конфигурационные файлы и проверяет значения чувствительных ключей
+type StructuredScanner struct {
+	keys []string
+}
+
+// NewStructuredScanner создает сканер; пустой список ключей заменяется встроенным
+func NewStructuredScanner(keys []string) *StructuredScanner {
+	if len(keys) == 0 {
+		keys = DefaultSensitiveKeys
+	}
+	lower := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lower = append(lower, strings.ToLower(key))
+	}
+	return &StructuredScanner{keys: lower}
+}
+
+// entry пара ключ-значение конфигурационного файла
+type entry struct {
+	path   string
+	value  string
+	line   int
+	column int
+}
+
+// Find разбирает файл по формату и возвращает литеральные значения чувствительных ключей
+// Ошибка означает, что файл не удалось разобрать
+func (s *StructuredScanner) Find(filePath, content string) ([]Finding, error) {
+	if lockFiles[filepath.Base(filePath)] {
+		return nil, nil
+	}
+
+	var entries []entry
+	var err error
+	switch StructuredFormat(filePath) {
+	case FormatJSON, FormatYAML:
+		entries, err = yamlEntries(content)
+	case FormatTOML:
+		entries, err = tomlEntries(content)
+	case FormatEnv:
+		entries = envEntries(content)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lineOffsets := lineStarts(content)
+	var findings []Finding
+	for _, e := range entries {
+		if !s.sensitiveKey(e.path) || AllowedValue(e.value) {
+			continue
+		}
+		offset := 0
+		if e.line-1 < len(lineOffsets) {
+			offset = lineOffsets[e.line-1] + e.column - 1
+		}
+		findings = append(findings, Finding{
+			Type:   StructuredTypeSecret,
+			Name:   e.path,
+			Value:  e.value,
+			Offset: offset,
+			Line:   e.line,
+			Column: e.column,
+		})
+	}
+	return findings, nil
+}
+
+// sensitiveKey проверяет последний сегмент пути ключа по шаблонам
+// Для элементов списка (api_tokens[0]) проверяется ключ списка
+func (s *StructuredScanner) sensitiveKey(path string) bool {
+	key := strings.ToLower(indexSuffix.ReplaceAllString(path, ""))
+	if dot := strings.LastIndex(key, "."); dot >= 0 {
+		key = key[dot+1:]
+	}
+	if key == "" {
+		return false
+	}
+
+	normalized := strings.NewReplacer("_", "", "-", "").Replace(key)
+	for _, suffix := range referenceSuffixes {
+		if strings.HasSuffix(normalized, suffix) {
+			return false
+		}
+	}
+	for _, word := range strings.FieldsFunc(key, func(r rune) bool { return r == '_' || r == '-' }) {
+		if quantityWords[word] {
+			return false
+		}
+	}
+
+	for _, pattern := range s.keys {
+		if ok, _ := doublestar.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedValue проверяет, что значение не является литеральным секретом:
+// пустое значение, ссылка на переменную окружения, ссылка на секрет-менеджер или заглушка
+func AllowedValue(value string) bool {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return true
+	}
+	lower := strings.ToLower(value)
+	for _, prefix := range vaultPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	if referencePattern.MatchString(value) || placeholderPasswords[lower] {
+		return true
+	}
+	// Заглушки из одного символа: ***, xxxx
+	return strings.Trim(value, value[:1]) == ""
+}
+
+// referencePattern подстановки вместо значения: ${VAR}, $VAR, $(cat token), {{ .Values.password }},
+// %env(DB_PASSWORD)%, %(password)s, <password>
+var referencePattern = regexp.MustCompile(`\$\{[^}]*\}|^\$[A-Za-z_][A-Za-z0-9_]*$|^\$\(.*\)$|^` + "`.*`$" + `|\{\{.*\}\}|%env\([^)]*\)%|^%\([^)]*\)s$|^<[^>]*>$`)
+
+// lineStarts возвращает смещения начала строк
+func lineStarts(content string) []int {
+	starts := []int{0}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// indexSuffix индексы элементов списка в конце пути ключа
+var indexSuffix = regexp.MustCompile(`(\[\d+\])+$`)
+
+// envItemPattern элемент списка переменных окружения: KEY=value (docker-compose environment)
+var envItemPattern = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_.\-]*)=(.*)$`)
+
+// yamlEntries разбирает YAML (в том числе из нескольких документов) и JSON (подмножество YAML)
+func yamlEntries(content string) ([]entry, error) {
+	var entries []entry
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	for {
+		var root yaml.Node
+		if err := decoder.Decode(&root); err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return nil, err
+		}
+		walkYAML(&root, "", &entries)
+	}
+}
+
+// walkYAML собирает скалярные значения с путями ключей
+func walkYAML(node *yaml.Node, path string, entries *[]entry) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			walkYAML(child, path, entries)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			walkYAML(node.Content[i+1], joinKey(path, node.Content[i].Value), entries)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			// Список KEY=value проверяется как отображение
+			if item.Kind == yaml.ScalarNode {
+				if match := envItemPattern.FindStringSubmatch(item.Value); match != nil {
+					*entries = append(*entries, entry{
+						path:   joinKey(path, match[1]),
+						value:  match[2],
+						line:   item.Line,
+						column: yamlColumn(item) + len(match[1]) + 1,
+					})
+					continue
+				}
+			}
+			walkYAML(item, fmt.Sprintf("%s[%d]", path, i), entries)
+		}
+	case yaml.ScalarNode:
+		// null, true/false, числа и пользовательские теги (!vault, !Ref) не являются литеральными секретами
+		switch node.Tag {
+		case "!!null", "!!bool", "!!int", "!!float":
+			return
+		}
+		if strings.HasPrefix(node.Tag, "!") && !strings.HasPrefix(node.Tag, "!!") {
+			return
+		}
+		*entries = append(*entries, entry{path: path, value: node.Value, line: node.Line, column: yamlColumn(node)})
+	}
+}
+
+// yamlColumn возвращает колонку начала значения без кавычек
+func yamlColumn(node *yaml.Node) int {
+	if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 {
+		return node.Column + 1
+	}
+	return node.Column
+}
+
+// joinKey добавляет ключ к пути
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// tomlTablePattern заголовок таблицы [a.b] или массива таблиц [[a.b]]
+var tomlTablePattern = regexp.MustCompile(`^\[\[?\s*([^\]]+?)\s*\]\]?\s*(?:#.*)?$`)
+
+// tomlEntries разбирает строковые значения TOML: таблицы, составные ключи, базовые и литеральные строки
+// Числа, массивы и встроенные таблицы не проверяются, строки продолжения массивов пропускаются
+func tomlEntries(content string) ([]entry, error) {
+	var entries []entry
+	table := ""
+	multiline := ""
+
+	for i, raw := range strings.Split(content, "\n") {
+		line := strings.TrimSpace(raw)
+		if multiline != "" {
+			if strings.Contains(line, multiline) {
+				multiline = ""
+			}
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if match := tomlTablePattern.FindStringSubmatch(line); match != nil {
+			table = tomlKey(match[1])
+			continue
+		}
+
+		eq := tomlAssignment(line)
+		if eq < 0 {
+			continue
+		}
+		key := joinKey(table, tomlKey(line[:eq]))
+		rest := strings.TrimLeft(line[eq+1:], " \t")
+		column := len(raw) - len(rest) + 1
+
+		for _, quote := range []string{`"""`, `'''`} {
+			if strings.HasPrefix(rest, quote) && !strings.Contains(rest[3:], quote) {
+				multiline = quote
+			}
+		}
+		if multiline != "" || rest == "" {
+			continue
+		}
+
+		switch rest[0] {
+		case '"':
+			end := closingQuote(rest, '"')
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated string", i+1)
+			}
+			value, err := strconv.Unquote(rest[:end+1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			entries = append(entries, entry{path: key, value: value, line: i + 1, column: column + 1})
+		case '\'':
+			end := strings.IndexByte(rest[1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated string", i+1)
+			}
+			entries = append(entries, entry{path: key, value: rest[1 : end+1], line: i + 1, column: column + 1})
+		}
+	}
+
+	return entries, nil
+}
+
+// tomlAssignment возвращает позицию '=' вне кавычек ключа
+func tomlAssignment(line string) int {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '=':
+			return i
+		}
+	}
+	return -1
+}
+
+// tomlKey нормализует ключ: убирает пробелы и кавычки вокруг сегментов
+func tomlKey(key string) string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(part), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
+
+// closingQuote возвращает позицию закрывающей кавычки с учетом экранирования, -1 - не найдена
+func closingQuote(s string, quote byte) int {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == quote {
+			return i
+		}
+	}
+	return -1
+}
+
+// envLinePattern строка .env: [export] KEY=value
+var envLinePattern = regexp.MustCompile(`^(\s*(?:export\s+)?)([A-Za-z_][A-Za-z0-9_.\-]*)\s*=\s*`)
+
+// envEntries разбирает .env файл; значение без кавычек заканчивается перед " #"
+func envEntries(content string) []entry {
+	var entries []entry
+	for i, line := range strings.Split(content, "\n") {
+		match := envLinePattern.FindStringSubmatchIndex(line)
+		if match == nil {
+			continue
+		}
+		key := line[match[4]:match[5]]
+		rest := strings.TrimRight(line[match[1]:], "\r")
+		column := match[1] + 1
+
+		var value string
+		switch {
+		case strings.HasPrefix(rest, `"`) || strings.HasPrefix(rest, "'"):
+			end := closingQuote(rest, rest[0])
+			if end < 0 {
+				end = len(rest)
+			}
+			value = rest[1:end]
+			column++
+		default:
+			if comment := strings.Index(rest, " #"); comment >= 0 {
+				rest = rest[:comment]
+			}
+			value = strings.TrimSpace(rest)
+		}
+		entries = append(entries, entry{path: key, value: value, line: i + 1, column: column})
+	}
+	return entries
+}
@@ -0,0 +1,98 @@
+package secrets
+
+import "testing"
+
+func TestStructuredFormat(t *testing.T) {
+	tests := map[string]string{
+		"package.json":       FormatJSON,
+		"deploy/values.yaml": FormatYAML,
+		"docker-compose.yml": FormatYAML,
+		"Cargo.toml":         FormatTOML,
+		".env":               FormatEnv,
+		".env.production":    FormatEnv,
+		"config/prod.env":    FormatEnv,
+		"main.go":            "",
+		"environment.go":     "",
+	}
+	for path, want := range tests {
+		if got := StructuredFormat(path); got != want {
+			t.Errorf("StructuredFormat(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestStructuredScanner_Find(t *testing.T) {
+	scanner := NewStructuredScanner(nil)
+
+	type want struct {
+		path   string
+		line   int
+		column int
+	}
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []want
+	}{
+		{"yaml nested", "docker-compose.yml", "services:\n  db:\n    environment:\n      PASSWORD: hunter2\n      USER: app\n", []want{{"services.db.environment.PASSWORD", 4, 17}}},
+		{"yaml env list", "docker-compose.yml", "services:\n  db:\n    environment:\n      - DB_PASSWORD=prod-pass\n", []want{{"services.db.environment.DB_PASSWORD", 4, 21}}},
+		{"yaml quoted", "app.yaml", "auth:\n  api_key: \"abc123\"\n", []want{{"auth.api_key", 2, 13}}},
+		{"yaml list of tokens", "app.yaml", "api_tokens:\n  - first-token\n", []want{{"api_tokens[0]", 2, 5}}},
+		{"yaml multi document", "k8s.yaml", "kind: A\n---\nstringData:\n  password: s3cret!\n", []want{{"stringData.password", 4, 13}}},
+		{"yaml env reference", "app.yaml", "db:\n  password: ${DB_PASSWORD}\n", nil},
+		{"yaml vault reference", "app.yaml", "db:\n  password: vault:secret/data/db#password\n", nil},
+		{"yaml vault tag", "app.yaml", "db:\n  password: !vault |\n    $ANSIBLE_VAULT;1.1;AES256\n", nil},
+		{"yaml empty and null", "app.yaml", "db:\n  password: \"\"\n  token: null\n  secret:\n", nil},
+		{"yaml helm template", "values.yaml", "password: \"{{ .Values.db.password }}\"\n", nil},
+		{"yaml reference key", "app.yaml", "password_file: /run/secrets/db\ntoken_url: https://auth/token\n", nil},
+		{"json", "config.json", "{\n\t\"database\": {\n\t\t\"password\": \"hunter2\"\n\t}\n}\n", []want{{"database.password", 3, 16}}},
+		{"json boolean", "config.json", `{"require_password": true}`, nil},
+		{"toml", "config.toml", "[database]\nhost = \"db\"\npassword = \"hunter2\" # prod\n", []want{{"database.password", 3, 13}}},
+		{"toml dotted and literal", "config.toml", "title = 'x'\nauth.token = 'abc-123'\nports = [\n  8080,\n]\n", []want{{"auth.token", 2, 15}}},
+		{"toml env reference", "config.toml", "[db]\npassword = \"${DB_PASSWORD}\"\n", nil},
+		{"env", ".env", "# local\nDB_HOST=localhost\nDB_PASSWORD=prod-pass\n", []want{{"DB_PASSWORD", 3, 13}}},
+		{"env export quoted", ".env.local", "export API_TOKEN=\"abc 123\" # comment\n", []want{{"API_TOKEN", 1, 19}}},
+		{"env empty and reference", ".env", "DB_PASSWORD=\nAPI_TOKEN=$VAULT_TOKEN\nSECRET=<secret>\n", nil},
+		{"lockfile", "package-lock.json", `{"token": "abc"}`, nil},
+		{"yaml numbers", "app.yaml", "max_tokens: 4096\npassword: 12345\ntoken_ratio: 0.5\n", nil},
+		{"yaml token counts", "app.yaml", "llm:\n  num_tokens: \"100000\"\n  token_limit: \"8k\"\n  max-tokens: \"4k\"\n", nil},
+		{"json tokenizer", "model.json", `{"tokenizer": "cl100k_base", "tokenizer_config": "gpt2"}`, nil},
+		{"json access token", "auth.json", `{"accessToken": "abc-123", "refresh_token": "def-456"}`, []want{{"accessToken", 1, 18}, {"refresh_token", 1, 46}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := scanner.Find(tt.file, tt.content)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(findings) != len(tt.want) {
+				t.Fatalf("expected %v, got %+v", tt.want, findings)
+			}
+			for i, f := range findings {
+				if f.Name != tt.want[i].path || f.Line != tt.want[i].line || f.Column != tt.want[i].column || f.Type != StructuredTypeSecret {
+					t.Errorf("got %+v, want %+v", f, tt.want[i])
+				}
+				if tt.content[f.Offset:f.Offset+len(f.Value)] != f.Value {
+					t.Errorf("offset %d does not point at value %q", f.Offset, f.Value)
+				}
+			}
+		})
+	}
+}
+
+func TestStructuredScanner_Keys(t *testing.T) {
+	content := "db:\n  dsn: postgres://db\n  password: hunter2\n"
+
+	findings, err := NewStructuredScanner([]string{"DSN"}).Find("app.yaml", content)
+	if err != nil || len(findings) != 1 || findings[0].Name != "db.dsn" {
+		t.Errorf("configured keys should replace defaults case-insensitively, got %+v (%v)", findings, err)
+	}
+}
+
+func TestStructuredScanner_InvalidFile(t *testing.T) {
+	if _, err := NewStructuredScanner(nil).Find("app.yaml", "a: [b\n"); err == nil {
+		t.Error("expected parse error for invalid YAML")
+	}
+}
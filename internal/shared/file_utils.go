@@ -8,28 +8,14 @@ import (
 
 // IsExceptionFile проверяет является ли файл исключением
 // CANONICAL VERSION - заменяет дублированные функции в BaseValidator и BaseAdvisor
-func IsExceptionFile(filePath string, exceptions []string, logger core.Logger) bool {
-	// Проверяем по путям из конфигурации
-	for _, exception := range exceptions {
-		if strings.Contains(filePath, exception) {
-			logger.Debug("file matched exception path", "file", filePath, "exception", exception)
-			return true
-		}
-	}
-
-	// Проверяем файлы документации
-	if IsDocumentationFile(filePath) {
-		logger.Debug("file is documentation", "file", filePath)
-		return true
+// filePath - путь от корня проекта (FileAnalysis.ProjectPath)
+func IsExceptionFile(filePath string, matcher *PathMatcher, logger core.Logger) bool {
+	// Встроенные исключения входят в шаблоны matcher, "!" в конфигурации их отменяет
+	pattern, excluded := matcher.Match(filePath)
+	if pattern != "" {
+		logger.Debug("file matched exception pattern", "file", filePath, "pattern", pattern, "excluded", excluded)
 	}
-
-	// Проверяем тестовые файлы
-	if IsTestFile(filePath) {
-		logger.Debug("file is test file", "file", filePath)
-		return true
-	}
-
-	return false
+	return excluded
 }
 
 // IsDocumentationFile проверяет является ли файл документацией
@@ -0,0 +1,313 @@
+package shared
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+)
+
+// Language язык исходного кода для лексического разбора
+type Language string
+
+const (
+	LangUnknown Language = ""
+	LangGo      Language = "go"
+	LangJS      Language = "js" // JavaScript и TypeScript
+	LangPython  Language = "python"
+	LangShell   Language = "shell"
+)
+
+// LanguageForPath определяет язык по расширению файла
+func LanguageForPath(filePath string) Language {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".go":
+		return LangGo
+	case ".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs":
+		return LangJS
+	case ".py":
+		return LangPython
+	case ".sh", ".bash", ".zsh":
+		return LangShell
+	}
+	return LangUnknown
+}
+
+// SpanKind вид участка исходного кода
+type SpanKind string
+
+const (
+	SpanCode    SpanKind = core.ScopeCode
+	SpanComment SpanKind = core.ScopeComment
+	SpanString  SpanKind = core.ScopeString
+)
+
+// Span участок комментария или строкового литерала [Start, End) в байтах
+// Комментарий включает маркеры (//, #, /* */), строка - только содержимое без кавычек
+type Span struct {
+	Kind  SpanKind
+	Start int
+	End   int
+}
+
+// Spans отсортированные непересекающиеся участки, все остальное - код
+type Spans []Span
+
+// KindAt возвращает вид участка по смещению
+func (s Spans) KindAt(offset int) SpanKind {
+	i := sort.Search(len(s), func(i int) bool { return s[i].End > offset })
+	if i < len(s) && s[i].Start <= offset {
+		return s[i].Kind
+	}
+	return SpanCode
+}
+
+// Mask заменяет пробелами все участки кроме указанных видов
+// Переводы строк и смещения сохраняются, поэтому номера строк и колонок не меняются
+func (s Spans) Mask(content string, keep ...SpanKind) string {
+	kept := func(kind SpanKind) bool {
+		for _, k := range keep {
+			if k == kind {
+				return true
+			}
+		}
+		return false
+	}
+
+	masked := []byte(content)
+	blank := func(start, end int) {
+		for i := start; i < end; i++ {
+			if masked[i] != '\n' {
+				masked[i] = ' '
+			}
+		}
+	}
+
+	pos := 0
+	for _, span := range s {
+		if !kept(SpanCode) {
+			blank(pos, span.Start)
+		}
+		if !kept(span.Kind) {
+			blank(span.Start, span.End)
+		}
+		pos = span.End
+	}
+	if !kept(SpanCode) {
+		blank(pos, len(masked))
+	}
+	return string(masked)
+}
+
+// ScopeContent оставляет в тексте только участки указанных видов (код, строки, комментарии)
+// Пустой scope или неизвестный язык - текст возвращается без изменений
+func ScopeContent(content string, lang Language, scope []SpanKind) string {
+	if len(scope) == 0 || lang == LangUnknown {
+		return content
+	}
+	return Tokenize(content, lang).Mask(content, scope...)
+}
+
+// Tokenize размечает комментарии и строковые литералы
+// Для неизвестного языка весь текст считается кодом
+func Tokenize(content string, lang Language) Spans {
+	if lang == LangUnknown {
+		return nil
+	}
+	l := &lexer{src: content, lang: lang}
+	l.run()
+	return l.spans
+}
+
+// lexer однопроходный разборщик комментариев и строк
+// Не разбирает регулярные выражения JS и heredoc shell: их содержимое считается кодом
+type lexer struct {
+	src   string
+	lang  Language
+	pos   int
+	spans Spans
+	// braces глубина фигурных скобок для каждой вложенной интерполяции ${...}
+	braces []int
+}
+
+// emit добавляет непустой участок
+func (l *lexer) emit(kind SpanKind, start, end int) {
+	if end > start {
+		l.spans = append(l.spans, Span{Kind: kind, Start: start, End: end})
+	}
+}
+
+// run разбирает код до конца текста
+func (l *lexer) run() {
+	for l.pos < len(l.src) {
+		if !l.code() {
+			return
+		}
+	}
+}
+
+// code разбирает один элемент кода, false - закрылась интерполяция ${...}
+func (l *lexer) code() bool {
+	rest := l.src[l.pos:]
+	ch := rest[0]
+
+	switch {
+	case l.lang != LangPython && l.lang != LangShell && strings.HasPrefix(rest, "//"):
+		l.lineComment()
+	case l.lang != LangPython && l.lang != LangShell && strings.HasPrefix(rest, "/*"):
+		end := strings.Index(rest[2:], "*/")
+		if end < 0 {
+			l.emit(SpanComment, l.pos, len(l.src))
+			l.pos = len(l.src)
+		} else {
+			l.emit(SpanComment, l.pos, l.pos+end+4)
+			l.pos += end + 4
+		}
+	case ch == '#' && l.lang == LangPython:
+		l.lineComment()
+	case ch == '#' && l.lang == LangShell && l.wordStart():
+		l.lineComment()
+	case ch == '"' || ch == '\'':
+		l.quoted(ch)
+	case ch == '`' && l.lang == LangGo:
+		l.pos++
+		l.raw('`')
+	case ch == '`' && l.lang == LangJS:
+		l.pos++
+		l.template('`')
+	case ch == '{' && len(l.braces) > 0:
+		l.braces[len(l.braces)-1]++
+		l.pos++
+	case ch == '}' && len(l.braces) > 0:
+		l.pos++
+		if l.braces[len(l.braces)-1] == 0 {
+			return false
+		}
+		l.braces[len(l.braces)-1]--
+	default:
+		l.pos++
+	}
+	return true
+}
+
+// wordStart проверяет что # в shell начинает слово, а не является частью $# или a#b
+func (l *lexer) wordStart() bool {
+	if l.pos == 0 {
+		return true
+	}
+	switch l.src[l.pos-1] {
+	case ' ', '\t', '\n', ';', '&', '|', '(', ')':
+		return true
+	}
+	return false
+}
+
+// lineComment размечает комментарий до конца строки
+func (l *lexer) lineComment() {
+	end := strings.IndexByte(l.src[l.pos:], '\n')
+	if end < 0 {
+		end = len(l.src) - l.pos
+	}
+	l.emit(SpanComment, l.pos, l.pos+end)
+	l.pos += end
+}
+
+// quoted разбирает строку в одинарных или двойных кавычках
+func (l *lexer) quoted(quote byte) {
+	if l.lang == LangPython && strings.HasPrefix(l.src[l.pos:], strings.Repeat(string(quote), 3)) {
+		l.tripleQuoted(quote)
+		return
+	}
+	if l.lang == LangShell {
+		l.pos++
+		if quote == '\'' {
+			l.raw('\'')
+		} else {
+			l.template('"')
+		}
+		return
+	}
+
+	l.pos++
+	start := l.pos
+	for l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case '\\':
+			l.pos += 2
+			continue
+		case quote:
+			l.emit(SpanString, start, l.pos)
+			l.pos++
+			return
+		case '\n':
+			// Незакрытая строка заканчивается на конце строки
+			l.emit(SpanString, start, l.pos)
+			return
+		}
+		l.pos++
+	}
+	l.emit(SpanString, start, len(l.src))
+}
+
+// tripleQuoted разбирает многострочную строку Python
+func (l *lexer) tripleQuoted(quote byte) {
+	delimiter := strings.Repeat(string(quote), 3)
+	l.pos += 3
+	start := l.pos
+	for l.pos < len(l.src) {
+		if l.src[l.pos] == '\\' {
+			l.pos += 2
+			continue
+		}
+		if strings.HasPrefix(l.src[l.pos:], delimiter) {
+			l.emit(SpanString, start, l.pos)
+			l.pos += 3
+			return
+		}
+		l.pos++
+	}
+	l.emit(SpanString, start, len(l.src))
+}
+
+// raw разбирает строку без экранирования до закрывающего символа
+// Открывающая кавычка уже пропущена
+func (l *lexer) raw(quote byte) {
+	start := l.pos
+	end := strings.IndexByte(l.src[l.pos:], quote)
+	if end < 0 {
+		l.emit(SpanString, start, len(l.src))
+		l.pos = len(l.src)
+		return
+	}
+	l.emit(SpanString, start, l.pos+end)
+	l.pos += end + 1
+}
+
+// template разбирает строку с интерполяцией ${...}: шаблон JS или строку shell в двойных кавычках
+// Открывающая кавычка уже пропущена, содержимое ${...} размечается как код
+func (l *lexer) template(quote byte) {
+	start := l.pos
+	for l.pos < len(l.src) {
+		switch {
+		case l.src[l.pos] == '\\':
+			l.pos += 2
+			continue
+		case l.src[l.pos] == quote:
+			l.emit(SpanString, start, l.pos)
+			l.pos++
+			return
+		case strings.HasPrefix(l.src[l.pos:], "${"):
+			l.emit(SpanString, start, l.pos)
+			l.pos += 2
+			l.braces = append(l.braces, 0)
+			for l.pos < len(l.src) && l.code() {
+			}
+			l.braces = l.braces[:len(l.braces)-1]
+			start = l.pos
+			continue
+		}
+		l.pos++
+	}
+	l.emit(SpanString, start, len(l.src))
+}
@@ -0,0 +1,147 @@
+package shared
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScopeContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		lang    Language
+		content string
+		scope   []SpanKind
+		want    string
+	}{
+		{
+			name:    "go trailing comment",
+			lang:    LangGo,
+			content: `x := 1 // exit here`,
+			scope:   []SpanKind{SpanCode},
+			want:    `x := 1             `,
+		},
+		{
+			name:    "go string containing comment marker",
+			lang:    LangGo,
+			content: `url := "http://host" + path`,
+			scope:   []SpanKind{SpanCode},
+			want:    `url := "           " + path`,
+		},
+		{
+			name:    "go escaped quote",
+			lang:    LangGo,
+			content: `s := "a\"b" + c`,
+			scope:   []SpanKind{SpanCode},
+			want:    `s := "    " + c`,
+		},
+		{
+			name:    "go block comment across lines",
+			lang:    LangGo,
+			content: "a /* one\ntwo */ b",
+			scope:   []SpanKind{SpanCode},
+			want:    "a       \n       b",
+		},
+		{
+			name:    "go raw string",
+			lang:    LangGo,
+			content: "s := `x // y` + z",
+			scope:   []SpanKind{SpanCode},
+			want:    "s := `      ` + z",
+		},
+		{
+			name:    "only strings",
+			lang:    LangGo,
+			content: `key := "abc" // "def"`,
+			scope:   []SpanKind{SpanString},
+			want:    `        abc          `,
+		},
+		{
+			name:    "only comments",
+			lang:    LangGo,
+			content: `key := "abc" // note`,
+			scope:   []SpanKind{SpanComment},
+			want:    `             // note`,
+		},
+		{
+			name:    "js template interpolation is code",
+			lang:    LangJS,
+			content: "s = `a ${x || 'b'} c`",
+			scope:   []SpanKind{SpanCode},
+			want:    "s = `  ${x || ' '}  `",
+		},
+		{
+			name:    "python hash comment and triple quotes",
+			lang:    LangPython,
+			content: "x = '''a\n# b''' # c",
+			scope:   []SpanKind{SpanCode},
+			want:    "x = ''' \n   '''    ",
+		},
+		{
+			name:    "python has no slash comments",
+			lang:    LangPython,
+			content: "x = a // b",
+			scope:   []SpanKind{SpanCode},
+			want:    "x = a // b",
+		},
+		{
+			name:    "shell expansion inside double quotes is code",
+			lang:    LangShell,
+			content: `echo "dir ${D:-/tmp}" # note`,
+			scope:   []SpanKind{SpanCode},
+			want:    `echo "    ${D:-/tmp}"       `,
+		},
+		{
+			name:    "shell hash inside word is code",
+			lang:    LangShell,
+			content: `echo $# a#b`,
+			scope:   []SpanKind{SpanCode},
+			want:    `echo $# a#b`,
+		},
+		{
+			name:    "unknown language is unchanged",
+			lang:    LangUnknown,
+			content: `a: "b" # c`,
+			scope:   []SpanKind{SpanCode},
+			want:    `a: "b" # c`,
+		},
+		{
+			name:    "unterminated string stops at line end",
+			lang:    LangGo,
+			content: "s := \"abc\nx()",
+			scope:   []SpanKind{SpanCode},
+			want:    "s := \"   \nx()",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScopeContent(tt.content, tt.lang, tt.scope)
+			if got != tt.want {
+				t.Errorf("ScopeContent(%q)\n got %q\nwant %q", tt.content, got, tt.want)
+			}
+			if len(got) != len(tt.content) || strings.Count(got, "\n") != strings.Count(tt.content, "\n") {
+				t.Error("masking must preserve offsets and line breaks")
+			}
+		})
+	}
+}
+
+func TestSpansKindAt(t *testing.T) {
+	content := `a := "s" // c`
+	spans := Tokenize(content, LangGo)
+
+	for offset, want := range map[int]SpanKind{0: SpanCode, 5: SpanCode, 6: SpanString, 7: SpanCode, 9: SpanComment, 12: SpanComment} {
+		if got := spans.KindAt(offset); got != want {
+			t.Errorf("KindAt(%d) = %s, want %s", offset, got, want)
+		}
+	}
+}
+
+func TestTokenize_Unterminated(t *testing.T) {
+	// Оборванный ввод не должен приводить к выходу за границы
+	for _, content := range []string{`"abc\`, "`abc", "/* abc", "s = `a ${b", `x = "\`} {
+		for _, lang := range []Language{LangGo, LangJS, LangPython, LangShell} {
+			ScopeContent(content, lang, []SpanKind{SpanCode})
+		}
+	}
+}
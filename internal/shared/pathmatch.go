@@ -0,0 +1,119 @@
+package shared
+
+import (
+	"path"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// pathPattern разобранный шаблон исключения
+type pathPattern struct {
+	source   string // исходная строка из конфигурации
+	glob     string
+	negate   bool // "!pattern" возвращает файл в проверку
+	dirOnly  bool // "dir/" совпадает только с директориями
+	anchored bool // шаблон со слешем сопоставляется с путем от корня проекта
+}
+
+// PathMatcher сопоставляет пути с шаблонами исключений по правилам .gitignore:
+// шаблон без слеша совпадает с именем файла или директории на любой глубине,
+// шаблон со слешем - с путем относительно корня проекта, "**" - любое число директорий,
+// "dir/" - только директория, "!" - отрицание. Побеждает последний совпавший шаблон
+type PathMatcher struct {
+	patterns []pathPattern
+}
+
+// NewPathMatcher собирает matcher из списков шаблонов, порядок списков сохраняется
+// Пустые строки и комментарии "#" пропускаются
+func NewPathMatcher(lists ...[]string) *PathMatcher {
+	matcher := &PathMatcher{}
+	for _, list := range lists {
+		for _, source := range list {
+			if pattern, ok := parsePathPattern(source); ok {
+				matcher.patterns = append(matcher.patterns, pattern)
+			}
+		}
+	}
+	return matcher
+}
+
+// parsePathPattern разбирает шаблон в стиле .gitignore
+func parsePathPattern(source string) (pathPattern, bool) {
+	glob := strings.TrimSpace(source)
+	if glob == "" || strings.HasPrefix(glob, "#") {
+		return pathPattern{}, false
+	}
+
+	pattern := pathPattern{source: source}
+	if strings.HasPrefix(glob, "!") {
+		pattern.negate = true
+		glob = glob[1:]
+	}
+	if strings.HasSuffix(glob, "/") {
+		pattern.dirOnly = true
+		glob = strings.TrimRight(glob, "/")
+	}
+	if strings.Contains(glob, "/") {
+		pattern.anchored = true
+		glob = strings.TrimPrefix(glob, "/")
+	}
+	if glob == "" {
+		return pathPattern{}, false
+	}
+
+	pattern.glob = glob
+	return pattern, true
+}
+
+// Match проверяет, исключен ли путь, и возвращает решивший шаблон
+// Путь должен быть относительным от корня проекта; у абсолютных путей вне проекта
+// совпадают только шаблоны без слеша
+func (m *PathMatcher) Match(filePath string) (string, bool) {
+	if m == nil || len(m.patterns) == 0 {
+		return "", false
+	}
+
+	cleaned := path.Clean(strings.ReplaceAll(filePath, "\\", "/"))
+	cleaned = strings.TrimPrefix(cleaned, "./")
+	components := strings.Split(strings.TrimPrefix(cleaned, "/"), "/")
+
+	matched, decided := false, ""
+	for _, pattern := range m.patterns {
+		if pattern.matches(cleaned, components) {
+			matched, decided = !pattern.negate, pattern.source
+		}
+	}
+	return decided, matched
+}
+
+// matches проверяет совпадение шаблона с файлом или одной из его директорий
+func (p pathPattern) matches(cleaned string, components []string) bool {
+	last := len(components) - 1
+
+	if !p.anchored {
+		for i, component := range components {
+			if i == last && p.dirOnly {
+				break
+			}
+			if ok, _ := doublestar.Match(p.glob, component); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	if strings.HasPrefix(cleaned, "/") {
+		return false
+	}
+	// Совпадение с директорией исключает все ее содержимое
+	for i := range components {
+		if i == last && p.dirOnly {
+			break
+		}
+		if ok, _ := doublestar.Match(p.glob, strings.Join(components[:i+1], "/")); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+)
+
+func TestPathMatcher(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		want     bool
+	}{
+		{"basename glob at any depth", []string{"*.md"}, "docs/guide/intro.md", true},
+		{"basename glob does not match other extension", []string{"*.md"}, "cmd/md.go", false},
+		{"generated files", []string{"*.gen.go"}, "internal/api/types.gen.go", true},
+		{"directory pattern matches directory", []string{"test/"}, "test/helper.go", true},
+		{"directory pattern at any depth", []string{"test/"}, "pkg/test/helper.go", true},
+		{"directory pattern is not a substring", []string{"test/"}, "latest/helper.go", false},
+		{"directory pattern does not match file", []string{"test/"}, "pkg/test", false},
+		{"doublestar", []string{"**/fixtures/**"}, "internal/a/fixtures/data.go", true},
+		{"anchored pattern from root", []string{"internal/legacy/*.go"}, "internal/legacy/old.go", true},
+		{"anchored pattern not at depth", []string{"internal/legacy/*.go"}, "pkg/internal/legacy/old.go", false},
+		{"anchored directory excludes contents", []string{"/vendor"}, "vendor/lib/a.go", true},
+		{"negation re-includes", []string{"internal/**", "!internal/critical/**"}, "internal/critical/pay.go", false},
+		{"last match wins", []string{"!internal/critical/**", "internal/**"}, "internal/critical/pay.go", true},
+		{"comments and blanks ignored", []string{"# comment", "", "main.go"}, "main.go", true},
+		{"windows separators", []string{"cmd/"}, `cmd\tool\main.go`, true},
+		{"absolute path outside project matches basename patterns", []string{"*_bench.go", "internal/**"}, "/tmp/x/internal/a_bench.go", true},
+		{"absolute path outside project ignores anchored patterns", []string{"internal/**"}, "/tmp/x/internal/a.go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got := NewPathMatcher(tt.patterns).Match(tt.path)
+			if got != tt.want {
+				t.Errorf("Match(%q) with %v = %v, want %v", tt.path, tt.patterns, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsExceptionFile_NegationOverridesBuiltin(t *testing.T) {
+	logger := core.NewTestLogger()
+
+	builtin := core.BuiltinExemptions("secrets")
+
+	if !IsExceptionFile("docs/setup.go", NewPathMatcher(builtin), logger) {
+		t.Error("docs directory should be a built-in exception for project-relative paths")
+	}
+	if IsExceptionFile("docs/setup.go", NewPathMatcher(builtin, []string{"!docs/*.go"}), logger) {
+		t.Error("negation should re-include a built-in exception")
+	}
+}
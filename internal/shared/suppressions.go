@@ -0,0 +1,124 @@
+package shared
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+)
+
+// Директива собирается из частей чтобы исходник не распознавался как подавление
+const (
+	suppressionDirective = "claude-hooks" + ":ignore"
+	nextLineSuffix       = "-next-line"
+
+	// SuppressAll правило, подавляющее нарушения всех валидаторов
+	SuppressAll = "all"
+
+	// InvalidSuppressionType тип нарушения для подавления без обоснования
+	InvalidSuppressionType = "invalid_suppression"
+)
+
+// suppressionPattern директива с опциональным суффиксом -next-line, правилами через запятую и обоснованием
+var suppressionPattern = regexp.MustCompile(regexp.QuoteMeta(suppressionDirective) + `(-next-line)?(?:\s+([A-Za-z0-9_,-]+))?(.*)$`)
+
+// commentClosers закрывающие токены комментариев, не входящие в обоснование
+var commentClosers = []string{"*/", "-->", "#}", "%>"}
+
+// Suppression inline директива подавления нарушений
+type Suppression struct {
+	Line       int      // строка директивы
+	TargetLine int      // строка, к которой применяется подавление
+	Rules      []string // имена валидаторов, типы нарушений или all
+	Reason     string   // обязательное обоснование
+	Text       string   // текст директивы для сравнения с исходным содержимым
+}
+
+// Valid проверяет что подавление содержит правила и обоснование
+func (s Suppression) Valid() bool {
+	return len(s.Rules) > 0 && s.Reason != ""
+}
+
+// Covers проверяет подавляет ли директива нарушение валидатора
+func (s Suppression) Covers(validator, violationType string) bool {
+	for _, rule := range s.Rules {
+		if rule == SuppressAll || rule == validator || rule == violationType {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSuppressions находит все inline директивы подавления в содержимом
+// Директива распознается в любом стиле комментариев: //, #, /* */, <!-- -->
+func ParseSuppressions(content string) []Suppression {
+	if !strings.Contains(content, suppressionDirective) {
+		return nil
+	}
+
+	var suppressions []Suppression
+	for i, line := range strings.Split(content, "\n") {
+		match := suppressionPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		suppression := Suppression{
+			Line:       i + 1,
+			TargetLine: i + 1,
+			Reason:     parseReason(match[3]),
+			Text:       strings.TrimSpace(match[0]),
+		}
+		if match[1] == nextLineSuffix {
+			suppression.TargetLine = i + 2
+		}
+		for _, rule := range strings.Split(match[2], ",") {
+			if rule = strings.TrimSpace(rule); rule != "" && rule != "--" {
+				suppression.Rules = append(suppression.Rules, rule)
+			}
+		}
+
+		suppressions = append(suppressions, suppression)
+	}
+
+	return suppressions
+}
+
+// parseReason извлекает обоснование после правил, отбрасывая разделитель и закрытие комментария
+func parseReason(rest string) string {
+	reason := strings.TrimSpace(rest)
+	for _, closer := range commentClosers {
+		reason = strings.TrimSpace(strings.TrimSuffix(reason, closer))
+	}
+	reason = strings.TrimPrefix(reason, "--")
+	return strings.TrimSpace(reason)
+}
+
+// InvalidSuppressionViolation создает нарушение для директивы без правил или обоснования
+func InvalidSuppressionViolation(suppression Suppression) core.Violation {
+	message := "Подавление " + suppressionDirective + " без обоснования запрещено"
+	if len(suppression.Rules) == 0 {
+		message = "Подавление " + suppressionDirective + " без указания правила запрещено"
+	}
+
+	return core.Violation{
+		Type:       InvalidSuppressionType,
+		Message:    message,
+		Suggestion: "Укажи правило и причину: " + suppressionDirective + " secrets -- тестовый вектор из RFC 7519",
+		Line:       suppression.Line,
+		Severity:   core.LevelCritical,
+	}
+}
+
+// FindSuppression возвращает директиву, подавляющую нарушение, если она есть
+func FindSuppression(suppressions []Suppression, validator string, violation core.Violation) (Suppression, bool) {
+	for _, suppression := range suppressions {
+		if !suppression.Valid() || suppression.TargetLine != violation.Line {
+			continue
+		}
+		if suppression.Covers(validator, violation.Type) {
+			return suppression, true
+		}
+	}
+	return Suppression{}, false
+}
@@ -0,0 +1,121 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+)
+
+// directive собирается из частей чтобы тестовый файл не содержал директив
+const directive = "claude-hooks" + ":ignore"
+
+func TestParseSuppressions(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantRules  []string
+		wantReason string
+		wantTarget int
+		wantValid  bool
+	}{
+		{
+			name:       "same line with separator",
+			content:    `token := "eyJ..." // ` + directive + ` secrets -- test vector from RFC 7519`,
+			wantRules:  []string{"secrets"},
+			wantReason: "test vector from RFC 7519",
+			wantTarget: 1,
+			wantValid:  true,
+		},
+		{
+			name:       "next line without separator",
+			content:    "# " + directive + "-next-line runtime_exit entry point must exit\nos.exit(1)",
+			wantRules:  []string{"runtime_exit"},
+			wantReason: "entry point must exit",
+			wantTarget: 2,
+			wantValid:  true,
+		},
+		{
+			name:       "several rules in block comment",
+			content:    "/* " + directive + " secrets,hardcoded_jwt -- fixture */",
+			wantRules:  []string{"secrets", "hardcoded_jwt"},
+			wantReason: "fixture",
+			wantTarget: 1,
+			wantValid:  true,
+		},
+		{
+			name:       "missing reason",
+			content:    "// " + directive + " secrets",
+			wantRules:  []string{"secrets"},
+			wantTarget: 1,
+			wantValid:  false,
+		},
+		{
+			name:       "missing reason with separator only",
+			content:    "<!-- " + directive + " all -- -->",
+			wantRules:  []string{"all"},
+			wantTarget: 1,
+			wantValid:  false,
+		},
+		{
+			name:       "missing rules",
+			content:    "// " + directive + " -- just because",
+			wantReason: "just because",
+			wantTarget: 1,
+			wantValid:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suppressions := ParseSuppressions(tt.content)
+			if len(suppressions) != 1 {
+				t.Fatalf("expected 1 suppression, got %d", len(suppressions))
+			}
+			got := suppressions[0]
+
+			if len(got.Rules) != len(tt.wantRules) {
+				t.Fatalf("expected rules %v, got %v", tt.wantRules, got.Rules)
+			}
+			for i := range tt.wantRules {
+				if got.Rules[i] != tt.wantRules[i] {
+					t.Errorf("expected rules %v, got %v", tt.wantRules, got.Rules)
+				}
+			}
+			if got.Reason != tt.wantReason {
+				t.Errorf("expected reason %q, got %q", tt.wantReason, got.Reason)
+			}
+			if got.TargetLine != tt.wantTarget {
+				t.Errorf("expected target line %d, got %d", tt.wantTarget, got.TargetLine)
+			}
+			if got.Valid() != tt.wantValid {
+				t.Errorf("expected valid=%v", tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestFindSuppression(t *testing.T) {
+	content := "line one\n// " + directive + "-next-line secrets -- fixture\nkey := \"secret\"\n// " + directive + " secrets\n"
+	suppressions := ParseSuppressions(content)
+
+	tests := []struct {
+		name      string
+		validator string
+		violation core.Violation
+		want      bool
+	}{
+		{"matches validator on target line", "secrets", core.Violation{Type: "hardcoded_secret", Line: 3}, true},
+		{"matches violation type", "other", core.Violation{Type: "secrets", Line: 3}, true},
+		{"different validator", "runtime_exit", core.Violation{Type: "runtime_exit_usage", Line: 3}, false},
+		{"different line", "secrets", core.Violation{Type: "hardcoded_secret", Line: 1}, false},
+		{"invalid suppression does not apply", "secrets", core.Violation{Type: "hardcoded_secret", Line: 4}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := FindSuppression(suppressions, tt.validator, tt.violation); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
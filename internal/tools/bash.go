@@ -2,30 +2,37 @@ package tools
 
 import (
 	"context"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aiseeq/claude-hooks/internal/core"
+	"github.com/aiseeq/claude-hooks/internal/secrets"
 )
 
+// credentialSuggestion how to pass credentials without putting them on the command line
+const credentialSuggestion = "Keep credentials in an env file (set -a; source .env) or a credential helper " +
+	"(gh auth, git credential, ~/.netrc, aws configure) and reference them as $VAR"
+
 // BashTool validator for bash commands
 type BashTool struct {
 	*BaseTool
 	blockedPatterns []string
+	credentials     *secrets.CommandScanner
+	allowlist       core.Allowlist
 }
 
 // NewBashTool creates new bash tool validator
-func NewBashTool(config core.ToolConfig, logger core.Logger) (*BashTool, error) {
+// allowlist is validators.secrets.allowlist: a value allowlisted once passes in files and commands alike
+func NewBashTool(config core.ToolConfig, allowlist []core.AllowlistEntry, logger core.Logger) (*BashTool, error) {
 	base := NewBaseTool("bash", config.Enabled, []string{"Bash"}, logger)
 
-	// Use BlockedPatterns from config, fallback to DangerousCommands for backwards compatibility
-	blockedPatterns := config.BlockedPatterns
-	if len(blockedPatterns) == 0 {
-		blockedPatterns = config.DangerousCommands
-	}
-
+	// Legacy dangerous_commands is rewritten to blocked_patterns by config migration
 	tool := &BashTool{
 		BaseTool:        base,
-		blockedPatterns: blockedPatterns,
+		blockedPatterns: config.BlockedPatterns,
+		credentials:     secrets.NewCommandScanner(config.RuleEnabled, nil),
+		allowlist:       core.NewAllowlist(allowlist),
 	}
 
 	return tool, nil
@@ -46,7 +53,9 @@ func (t *BashTool) ValidateTool(ctx context.Context, input *core.ToolInput) (*co
 		return &core.ValidationResult{IsValid: true}, nil
 	}
 
-	t.logger.Debug("validating bash command", "command", command)
+	// Credentials are masked before the command reaches logs and messages
+	found := t.credentials.Find(command)
+	t.logger.Debug("validating bash command", "command", secrets.MaskFindings(command, found))
 
 	var violations []core.Violation
 
@@ -65,6 +74,24 @@ func (t *BashTool) ValidateTool(ctx context.Context, input *core.ToolInput) (*co
 		}
 	}
 
+	now := time.Now()
+	for _, finding := range found {
+		fingerprint := finding.Fingerprint()
+		if entry, ok := t.allowlist.Match(fingerprint, now); ok {
+			t.logger.Debug("allowlisted credential skipped", "type", finding.Type, "comment", entry.Comment)
+			continue
+		}
+		violations = append(violations, core.Violation{
+			Type:        finding.Type,
+			Message:     "Credential in bash command: " + maskedFinding(finding),
+			Suggestion:  credentialSuggestion,
+			Severity:    core.LevelCritical,
+			Line:        finding.Line,
+			Column:      finding.Column,
+			Fingerprint: fingerprint,
+		})
+	}
+
 	isValid := len(violations) == 0
 
 	return &core.ValidationResult{
@@ -72,3 +99,12 @@ func (t *BashTool) ValidateTool(ctx context.Context, input *core.ToolInput) (*co
 		Violations: violations,
 	}, nil
 }
+
+// maskedFinding describes a finding without revealing the secret: "DB_PASSWORD: prod****"
+func maskedFinding(finding secrets.Finding) string {
+	label := finding.Name
+	if label == "" {
+		label = finding.Type
+	}
+	return fmt.Sprintf("%s: %s", label, secrets.Mask(finding.Value))
+}
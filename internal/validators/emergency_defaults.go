@@ -4,57 +4,69 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/aiseeq/claude-hooks/internal/core"
+	"github.com/aiseeq/claude-hooks/internal/shared"
 )
 
 // EmergencyDefaultsValidator проверяет использование запасных значений
 type EmergencyDefaultsValidator struct {
 	*BaseValidator
 	caseSensitive bool
-	patterns      []*regexp.Regexp
+	languages     map[shared.Language]bool // nil - все языки
+	// Настройки команды по языку: допустимые и дополнительные запрещенные конструкции
+	allowed map[shared.Language][]*regexp.Regexp
+	custom  map[shared.Language][]defaultsRule
 }
 
 // NewEmergencyDefaultsValidator создает новый валидатор запасных значений
 func NewEmergencyDefaultsValidator(config core.ValidatorConfig, logger core.Logger) (*EmergencyDefaultsValidator, error) {
-	baseValidator := NewBaseValidator("emergency_defaults", config.Enabled, config.ExceptionPaths, logger)
+	baseValidator := NewBaseValidator("emergency_defaults", config.Enabled, exceptionPatterns("emergency_defaults", config), logger)
 
 	validator := &EmergencyDefaultsValidator{
 		BaseValidator: baseValidator,
 		caseSensitive: config.CaseSensitive,
+		languages:     configLanguages(config),
+		allowed:       make(map[shared.Language][]*regexp.Regexp),
+		custom:        make(map[shared.Language][]defaultsRule),
 	}
+	baseValidator.setMatchScope(config, shared.SpanCode)
 
-	// Компилируем паттерны для поиска запасных значений
-	if err := validator.compilePatterns(); err != nil {
+	// Компилируем паттерны команды для языковых наборов
+	if err := validator.compilePackPatterns(config.Packs); err != nil {
 		return nil, fmt.Errorf("failed to compile patterns: %w", err)
 	}
 
 	return validator, nil
 }
 
-// compilePatterns компилирует regex паттерны
-// Согласно CLAUDE.md: запрещены любые default возвраты при ошибках
-func (v *EmergencyDefaultsValidator) compilePatterns() error {
-	// Критичные блокирующие паттерны - f-a-l-l-b-a-c-k разбит чтобы хук не блокировал сам себя
-	word := "fall" + "back"
-	criticalPatterns := []string{
-		`(?i)\b` + word + `\b`,                          // запрещённое слово
-		`\|\|\s*["'\d]`,                                 // || "value" или || 123 (JS/TS default)
-		`\?\?\s*["'\d]`,                                 // ?? "value" или ?? 123 (nullish coalescing)
-		`:-[^}]+}`,                                      // ${VAR:-value} (bash default)
-		`getenv\([^)]*,\s*[^)]+\)`,                      // getenv с default значением
-	}
-
-	// Компилируем критичные паттерны (блокирующие)
-	for _, pattern := range criticalPatterns {
-		compiled, err := regexp.Compile(pattern)
-		if err != nil {
-			return fmt.Errorf("failed to compile critical pattern %s: %w", pattern, err)
+// compilePackPatterns компилирует допустимые и запрещенные конструкции команды из packs
+func (v *EmergencyDefaultsValidator) compilePackPatterns(packs map[string]core.DefaultsPackConfig) error {
+	for name, pack := range packs {
+		language := shared.Language(strings.ToLower(name))
+		for _, pattern := range pack.Allowed {
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("failed to compile allowed pattern %s: %w", pattern, err)
+			}
+			v.allowed[language] = append(v.allowed[language], compiled)
+		}
+		for _, pattern := range pack.Patterns {
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("failed to compile custom pattern %s: %w", pattern, err)
+			}
+			v.custom[language] = append(v.custom[language], defaultsRule{
+				violationType: "custom_default",
+				pattern:       compiled,
+				message:       "Обнаружена запрещённая конструкция default значения",
+				suggestion:    "Используй explicit validation вместо default значений",
+				severity:      core.LevelCritical,
+			})
 		}
-		v.patterns = append(v.patterns, compiled)
 	}
-
 	return nil
 }
 
@@ -65,217 +77,103 @@ func (v *EmergencyDefaultsValidator) Validate(ctx context.Context, file *core.Fi
 	}
 
 	// Проверяем исключения
-	if v.IsExceptionFile(file.Path) {
+	if v.IsExceptionFile(file.ProjectPath()) {
 		v.logger.Debug("file is exception, skipping validation", "file", file.Path)
 		return &core.ValidationResult{IsValid: true}, nil
 	}
 
-	// Проверяем поддерживаемые типы файлов
-	supportedExtensions := []string{".go", ".ts", ".js", ".tsx", ".jsx", ".py", ".sh", ".bash"}
-	if !isSupportedFileType(file.Path, supportedExtensions) {
-		v.logger.Debug("file type not supported, skipping", "file", file.Path)
+	// Выбираем набор правил по языку файла
+	pack := defaultsPackFor(fileExtension(file.Extension, file.Path))
+	if pack == nil || (v.languages != nil && !v.languages[pack.language]) {
+		v.logger.Debug("language not checked, skipping", "file", file.Path)
 		return &core.ValidationResult{IsValid: true}, nil
 	}
 
-	// Ищем нарушения
-	violations := v.findViolations(file.Content)
+	// Ищем нарушения только в коде: комментарии и содержимое строк заменены пробелами
+	violations := v.findViolations(v.ScopedContent(file), pack)
 	if len(violations) == 0 {
 		return &core.ValidationResult{IsValid: true}, nil
 	}
 
-	// Все найденные нарушения критичные - блокируем
+	// Блокируем только критичные нарушения, остальные конструкции - предупреждения
 	return &core.ValidationResult{
-		IsValid:     false,
+		IsValid:     !hasCriticalViolation(violations),
 		Violations:  violations,
-		Suggestions: v.generateSuggestions(violations),
+		Suggestions: v.generateSuggestions(pack),
 	}, nil
 }
 
-// findViolations находит нарушения
-func (v *EmergencyDefaultsValidator) findViolations(content string) []core.Violation {
+// findViolations находит нарушения правил набора, в каждой строке сообщается не более одного
+// Правила проверяются по приоритету: запрещённое слово, конструкции языка, паттерны команды
+// Колонки считаются от начала строки, а не от первого непробельного символа
+func (v *EmergencyDefaultsValidator) findViolations(content string, pack *defaultsPack) []core.Violation {
 	var violations []core.Violation
-	lines := strings.Split(content, "\n")
-	word := "fall" + "back"
-
-	for lineNum, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed == "" {
-			continue
-		}
-
-		// Пропускаем нормальные конструкции Go
-		if v.isNormalGoConstruct(trimmed) {
-			continue
-		}
-
-		// Пропускаем комментарии
-		if v.isComment(trimmed) {
-			continue
-		}
-
-		// Проверяем запрещённое слово
-		if strings.Contains(strings.ToLower(trimmed), word) {
-			violation := core.Violation{
-				Type:       "critical_default",
-				Message:    "Обнаружено запрещённое слово в исполняемом коде",
-				Suggestion: "Используй explicit validation вместо default значений",
-				Severity:   core.LevelCritical,
-				Line:       lineNum + 1,
-				Column:     strings.Index(strings.ToLower(trimmed), word) + 1,
-			}
-			violations = append(violations, violation)
-			continue
-		}
 
-		// Проверяем || с литералом (JS/TS default pattern)
-		if strings.Contains(trimmed, "||") && v.hasLiteralAfterOr(trimmed) {
-			violation := core.Violation{
-				Type:       "critical_default",
-				Message:    "Обнаружен || default паттерн",
-				Suggestion: "Используй explicit validation: if (!value) throw new Error('required')",
-				Severity:   core.LevelCritical,
-				Line:       lineNum + 1,
-				Column:     strings.Index(trimmed, "||") + 1,
-			}
-			violations = append(violations, violation)
-			continue
+	skipped := make(map[int]bool)
+	for lineNum, line := range strings.Split(content, "\n") {
+		if v.isAllowedConstruct(pack, strings.TrimSpace(line)) {
+			skipped[lineNum+1] = true
 		}
+	}
 
-		// Проверяем ?? с литералом (nullish coalescing)
-		if strings.Contains(trimmed, "??") && v.hasLiteralAfterNullish(trimmed) {
-			violation := core.Violation{
-				Type:       "critical_default",
-				Message:    "Обнаружен ?? default паттерн",
-				Suggestion: "Используй explicit validation вместо nullish coalescing с default",
-				Severity:   core.LevelCritical,
-				Line:       lineNum + 1,
-				Column:     strings.Index(trimmed, "??") + 1,
+	rules := append([]defaultsRule{defaultsWordRule}, pack.rules...)
+	rules = append(rules, v.custom[pack.language]...)
+	for _, rule := range rules {
+		for _, loc := range rule.pattern.FindAllStringSubmatchIndex(content, -1) {
+			if rule.valid != nil && !rule.valid(submatches(content, loc)) {
+				continue
 			}
-			violations = append(violations, violation)
-			continue
-		}
-
-		// Проверяем bash ${VAR:-default}
-		if strings.Contains(trimmed, ":-") && strings.Contains(trimmed, "}") {
-			violation := core.Violation{
-				Type:       "critical_default",
-				Message:    "Обнаружен bash default паттерн ${VAR:-value}",
-				Suggestion: "Используй explicit проверку: if [ -z \"$VAR\" ]; then error; fi",
-				Severity:   core.LevelCritical,
-				Line:       lineNum + 1,
-				Column:     strings.Index(trimmed, ":-") + 1,
+			start := loc[2*rule.group]
+			line := strings.Count(content[:start], "\n") + 1
+			if skipped[line] {
+				continue
 			}
-			violations = append(violations, violation)
-			continue
+			skipped[line] = true
+
+			violations = append(violations, core.Violation{
+				Type:       rule.violationType,
+				Message:    rule.message,
+				Suggestion: rule.suggestion,
+				Severity:   rule.severity,
+				Line:       line,
+				Column:     start - strings.LastIndexByte(content[:start], '\n'),
+			})
 		}
 	}
 
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Line < violations[j].Line })
 	return violations
 }
 
-// hasLiteralAfterOr проверяет есть ли литерал после ||
-func (v *EmergencyDefaultsValidator) hasLiteralAfterOr(line string) bool {
-	idx := strings.Index(line, "||")
-	if idx == -1 {
-		return false
-	}
-	after := strings.TrimSpace(line[idx+2:])
-	// Проверяем начинается ли с кавычки или цифры
-	if len(after) > 0 {
-		ch := after[0]
-		return ch == '"' || ch == '\'' || ch == '`' || (ch >= '0' && ch <= '9')
+// submatches возвращает тексты групп совпадения по индексам
+func submatches(content string, loc []int) []string {
+	groups := make([]string, len(loc)/2)
+	for i := range groups {
+		if loc[2*i] >= 0 {
+			groups[i] = content[loc[2*i]:loc[2*i+1]]
+		}
 	}
-	return false
+	return groups
 }
 
-// hasLiteralAfterNullish проверяет есть ли литерал после ??
-func (v *EmergencyDefaultsValidator) hasLiteralAfterNullish(line string) bool {
-	idx := strings.Index(line, "??")
-	if idx == -1 {
+// isAllowedConstruct проверяет допустимые конструкции: общие для языков и заданные командой
+func (v *EmergencyDefaultsValidator) isAllowedConstruct(pack *defaultsPack, line string) bool {
+	if line == "" {
 		return false
 	}
-	after := strings.TrimSpace(line[idx+2:])
-	// Проверяем начинается ли с кавычки или цифры
-	if len(after) > 0 {
-		ch := after[0]
-		return ch == '"' || ch == '\'' || ch == '`' || (ch >= '0' && ch <= '9')
-	}
-	return false
-}
-
-// isNormalGoConstruct проверяет нормальные конструкции языка
-func (v *EmergencyDefaultsValidator) isNormalGoConstruct(line string) bool {
-	// Go switch/select default case
-	if strings.Contains(line, "default:") {
-		return true
-	}
-
-	// Go struct tags
-	if strings.Contains(line, "`") && strings.Contains(line, "default") {
-		return true
-	}
-
-	// Go функции с default в имени
-	if strings.Contains(line, "func") && strings.Contains(line, "Default") {
-		return true
-	}
-
-	return false
-}
-
-// isComment проверяет является ли строка комментарием
-func (v *EmergencyDefaultsValidator) isComment(line string) bool {
-	// Go/JS/TS однострочные комментарии
-	if strings.HasPrefix(line, "//") {
-		return true
-	}
-
-	// Python/Shell комментарии
-	if strings.HasPrefix(line, "#") {
-		return true
-	}
-
-	// Многострочные комментарии (начало)
-	if strings.HasPrefix(line, "/*") || strings.HasPrefix(line, "/**") {
-		return true
-	}
-
-	// Строки внутри многострочного комментария (обычно начинаются с *)
-	if strings.HasPrefix(line, "*") {
-		return true
+	for _, patterns := range [][]*regexp.Regexp{defaultsAllowedCommon, pack.allowed, v.allowed[pack.language]} {
+		for _, pattern := range patterns {
+			if pattern.MatchString(line) {
+				return true
+			}
+		}
 	}
-
 	return false
 }
 
-// generateSuggestions генерирует предложения по исправлению
-func (v *EmergencyDefaultsValidator) generateSuggestions(violations []core.Violation) []string {
-	return []string{
-		"Удали default значения из кода",
-		"Используй explicit validation: if value == \"\" { return errors.New(\"required\") }",
-		"Ошибки конфигурации должны быть явными, не скрытыми default значениями",
-	}
-}
-
-// IsExceptionFile переопределяет базовый метод с дополнительной логикой
-func (v *EmergencyDefaultsValidator) IsExceptionFile(filePath string) bool {
-	// Базовые исключения
-	if v.BaseValidator.IsExceptionFile(filePath) {
-		return true
-	}
-
-	// Дополнительные исключения
-	emergencyExceptions := []string{
-		"/test-config.", "/fixture", "/mock", "/stub",
-		".example", ".sample", ".template",
-	}
-
-	for _, exception := range emergencyExceptions {
-		if strings.Contains(filePath, exception) {
-			v.logger.Debug("file matched exception", "file", filePath, "exception", exception)
-			return true
-		}
-	}
-
-	return false
+// generateSuggestions генерирует предложения по исправлению для языка файла
+func (v *EmergencyDefaultsValidator) generateSuggestions(pack *defaultsPack) []string {
+	suggestions := []string{"Удали default значения из кода"}
+	suggestions = append(suggestions, pack.suggestions...)
+	return append(suggestions, "Ошибки конфигурации должны быть явными, не скрытыми default значениями")
 }
@@ -0,0 +1,193 @@
+package validators
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/aiseeq/claude-hooks/internal/core"
+	"github.com/aiseeq/claude-hooks/internal/shared"
+)
+
+// defaultsRule конструкция запасного значения
+// Паттерн ищется по всему коду без комментариев и содержимого строк (кавычки сохраняются),
+// поэтому может захватывать несколько строк; нарушение сообщается в строке начала группы group
+type defaultsRule struct {
+	violationType string
+	pattern       *regexp.Regexp
+	group         int
+	// valid дополнительно проверяет совпадение, например одну и ту же переменную в условии и присваивании
+	valid      func(match []string) bool
+	message    string
+	suggestion string
+	severity   core.Level
+}
+
+// defaultsPack правила запасных значений для одного языка
+type defaultsPack struct {
+	language   shared.Language
+	extensions []string
+	rules      []defaultsRule
+	// allowed конструкции языка, где слово default или похожий синтаксис допустимы: такие строки пропускаются
+	allowed []*regexp.Regexp
+	// suggestions рекомендации по исправлению для языка
+	suggestions []string
+}
+
+// literalStart начало литерала в коде: кавычка или цифра
+const literalStart = "[\"'`\\d]"
+
+// pythonLiteralStart начало литерала Python: строка с префиксом (r"", Rb"", F"") или литерал без него
+// Префикс без кавычки - это идентификатор (b2, f1), а не литерал
+const pythonLiteralStart = `(?:[rRbBuUfF]{1,2}["']|["'\d])`
+
+// callArgs аргументы вызова с одним уровнем вложенных скобок: getenv(key("X"), ...)
+const callArgs = `(?:[^()]|\([^()]*\))*?`
+
+// defaultsWordRule запрещенное слово, общее для всех языков - f-a-l-l-b-a-c-k разбит чтобы хук не блокировал сам себя
+var defaultsWordRule = defaultsRule{
+	violationType: "critical_default",
+	pattern:       regexp.MustCompile(`(?i)` + "fall" + "back"),
+	message:       "Обнаружено запрещённое слово в исполняемом коде",
+	suggestion:    "Используй explicit validation вместо default значений",
+	severity:      core.LevelCritical,
+}
+
+// defaultsAllowedCommon допустимые конструкции всех языков: default ветка switch, struct теги, функции Default*
+var defaultsAllowedCommon = compileDefaultsPatterns(
+	`\bdefault\s*:`,
+	"`[^`\\n]*`.*default|default.*`",
+	`\bfunc\b.*Default`,
+)
+
+// defaultsPacks встроенные наборы правил по языкам
+// || и ?? в Go - логические операторы, а не запасные значения, поэтому в наборе Go их нет
+var defaultsPacks = []*defaultsPack{
+	{
+		language:   shared.LangGo,
+		extensions: []string{".go"},
+		rules: []defaultsRule{
+			{
+				violationType: "empty_check_default",
+				pattern:       regexp.MustCompile(`\bif\s+([\w.]+)\s*==\s*""\s*\{\s*([\w.]+)\s*=\s*` + literalStart),
+				valid:         func(match []string) bool { return match[1] == match[2] },
+				message:       "Обнаружено запасное значение: if v == \"\" { v = \"...\" }",
+				suggestion:    "Верни ошибку, если значение не задано: if v == \"\" { return errors.New(\"v is required\") }",
+				severity:      core.LevelWarning,
+			},
+			{
+				violationType: "cmp_or_default",
+				pattern:       regexp.MustCompile(`\bcmp\.Or\s*\(` + callArgs + `,\s*` + literalStart),
+				message:       "Обнаружено запасное значение в cmp.Or",
+				suggestion:    "Проверяй значение явно и возвращай ошибку вместо cmp.Or с литералом",
+				severity:      core.LevelWarning,
+			},
+		},
+		suggestions: []string{
+			"Используй explicit validation: if value == \"\" { return errors.New(\"required\") }",
+		},
+	},
+	{
+		language:   shared.LangJS,
+		extensions: []string{".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs"},
+		rules: []defaultsRule{
+			{
+				violationType: "or_default",
+				pattern:       regexp.MustCompile(`\|\|\s*` + literalStart),
+				message:       "Обнаружен || default паттерн",
+				suggestion:    "Используй explicit validation: if (!value) throw new Error('required')",
+				severity:      core.LevelWarning,
+			},
+			{
+				violationType: "nullish_default",
+				pattern:       regexp.MustCompile(`\?\?\s*` + literalStart),
+				message:       "Обнаружен ?? default паттерн",
+				suggestion:    "Используй explicit validation вместо nullish coalescing с default",
+				severity:      core.LevelWarning,
+			},
+			{
+				violationType: "destructuring_default",
+				pattern:       regexp.MustCompile(`(?:\(\s*|\b(?:const|let|var)\s+)\{[^{}]*?\b(\w+\s*=\s*` + literalStart + `)[^{}]*\}`),
+				group:         1,
+				message:       "Обнаружено default значение в деструктуризации",
+				suggestion:    "Проверяй обязательные поля явно: if (port === undefined) throw new Error('port is required')",
+				severity:      core.LevelWarning,
+			},
+		},
+		// Счетчики: (counts[key] || 0) + 1
+		allowed: compileDefaultsPatterns(`\(\s*[\w.\[\]"']+\s*(?:\|\||\?\?)\s*0\s*\)\s*[+-]`),
+		suggestions: []string{
+			"Используй explicit validation: if (value === undefined) throw new Error('required')",
+		},
+	},
+	{
+		language:   shared.LangPython,
+		extensions: []string{".py"},
+		rules: []defaultsRule{
+			{
+				violationType: "getenv_default",
+				pattern:       regexp.MustCompile(`\bos\.(?:getenv|environ\.get)\s*\(` + callArgs + `,\s*` + pythonLiteralStart),
+				message:       "Обнаружено default значение переменной окружения",
+				suggestion:    "Используй os.environ[\"X\"] или проверяй значение и бросай исключение",
+				severity:      core.LevelCritical,
+			},
+			{
+				violationType: "dict_get_default",
+				pattern:       regexp.MustCompile(`\.get\s*\(` + callArgs + `,\s*` + pythonLiteralStart),
+				message:       "Обнаружено default значение в dict.get",
+				suggestion:    "Используй d[key] или проверяй наличие ключа явно",
+				severity:      core.LevelWarning,
+			},
+			{
+				violationType: "or_default",
+				pattern:       regexp.MustCompile(`\bor\s+` + pythonLiteralStart),
+				message:       "Обнаружен or default паттерн",
+				suggestion:    "Используй explicit validation: if not value: raise ValueError(\"required\")",
+				severity:      core.LevelWarning,
+			},
+		},
+		// Счетчики: counts.get(key, 0) + 1
+		allowed: compileDefaultsPatterns(`\.get\s*\(` + callArgs + `,\s*0\s*\)\s*[+-]`),
+		suggestions: []string{
+			"Используй explicit validation: if not value: raise ValueError(\"value is required\")",
+		},
+	},
+	{
+		language:   shared.LangShell,
+		extensions: []string{".sh", ".bash"},
+		rules: []defaultsRule{
+			{
+				violationType: "shell_default",
+				pattern:       regexp.MustCompile(`\$\{\w+(:[-=])[^}]`), // ${VAR:-} только проверяет, задана ли переменная
+				group:         1,
+				message:       "Обнаружен bash default паттерн ${VAR:-value}",
+				suggestion:    "Используй explicit проверку: if [ -z \"$VAR\" ]; then error; fi",
+				severity:      core.LevelCritical,
+			},
+		},
+		suggestions: []string{
+			"Используй ${VAR:?VAR is required} для обязательных переменных",
+		},
+	},
+}
+
+// compileDefaultsPatterns компилирует встроенные паттерны набора
+func compileDefaultsPatterns(patterns ...string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		compiled = append(compiled, regexp.MustCompile(pattern))
+	}
+	return compiled
+}
+
+// defaultsPackFor выбирает набор правил по расширению файла
+func defaultsPackFor(extension string) *defaultsPack {
+	extension = strings.ToLower(extension)
+	for _, pack := range defaultsPacks {
+		for _, ext := range pack.extensions {
+			if ext == extension {
+				return pack
+			}
+		}
+	}
+	return nil
+}
@@ -48,6 +48,26 @@ func TestEmergencyDefaultsValidator_BlocksFallback(t *testing.T) {
 			content:   "// fallback to default value if empty",
 			wantBlock: false,
 		},
+		{
+			name:      "allows fallback in trailing comment",
+			content:   "value := load() // no fallback here",
+			wantBlock: false,
+		},
+		{
+			name:      "allows fallback in block comment",
+			content:   "/*\nfallback is forbidden\n*/",
+			wantBlock: false,
+		},
+		{
+			name:      "allows fallback in string literal",
+			content:   "return errors.New(\"fallback is not configured\")",
+			wantBlock: false,
+		},
+		{
+			name:      "blocks fallback after string with comment marker",
+			content:   "url := \"http://host\"; useFallback(url)",
+			wantBlock: true,
+		},
 		{
 			name:      "warns but does not block || pattern",
 			content:   "value := x || \"default\"",
@@ -163,3 +183,175 @@ func TestEmergencyDefaultsValidator_ExceptionFiles(t *testing.T) {
 		})
 	}
 }
+
+func TestEmergencyDefaultsValidator_ExceptionGlobs(t *testing.T) {
+	logger := core.NewTestLogger()
+	config := core.ValidatorConfig{
+		Enabled:        true,
+		ExceptionPaths: []string{"test/", "internal/**"},
+		ExceptionFiles: []string{"*.gen.go", "!internal/critical/**"},
+	}
+
+	validator, err := NewEmergencyDefaultsValidator(config, logger)
+	if err != nil {
+		t.Fatalf("failed to create validator: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		wantBlock bool
+	}{
+		{"exception_files glob", "/work/api/types.gen.go", false},
+		{"directory is not a substring", "/work/latest/handler.go", true},
+		{"relative to project root", "/work/internal/service.go", false},
+		{"negation re-includes", "/work/internal/critical/pay.go", true},
+		{"fixtures are a built-in exception", "/work/pkg/fixtures/data.go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := &core.FileAnalysis{
+				Path:    tt.path,
+				Content: "func useFallback() {}",
+			}
+			file.SetProjectRoot("/work")
+
+			result, err := validator.Validate(context.Background(), file)
+			if err != nil {
+				t.Fatalf("validation failed: %v", err)
+			}
+			if tt.wantBlock == result.IsValid {
+				t.Errorf("expected block=%v, got valid=%v", tt.wantBlock, result.IsValid)
+			}
+		})
+	}
+}
+
+func TestEmergencyDefaultsValidator_LanguagePacks(t *testing.T) {
+	logger := core.NewTestLogger()
+	validator, err := NewEmergencyDefaultsValidator(core.ValidatorConfig{Enabled: true}, logger)
+	if err != nil {
+		t.Fatalf("failed to create validator: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		content   string
+		wantType  string // "" - нарушений нет
+		wantBlock bool
+	}{
+		{"python slice is not a default", "app.py", "print(f\"{x[:-1]}\")", "", false},
+		{"python set of slice is not a default", "app.py", "tail = {x[:-1]}", "", false},
+		{"python getenv default", "app.py", "port = os.getenv(\"PORT\", \"8080\")", "getenv_default", true},
+		{"python environ.get default", "app.py", "host = os.environ.get(\"HOST\", 'localhost')", "getenv_default", true},
+		{"python getenv without default", "app.py", "port = os.getenv(\"PORT\")", "", false},
+		{"python dict.get default", "app.py", "name = cfg.get(\"name\", \"anonymous\")", "dict_get_default", false},
+		{"python counter idiom", "app.py", "counts[k] = counts.get(k, 0) + 1", "", false},
+		{"python or default", "app.py", "name = value or \"guest\"", "or_default", false},
+		{"python or prefixed string default", "app.py", "pattern = custom or rb\"\\d+\"", "or_default", false},
+		{"python or identifier is not a default", "app.py", "name = first or b2", "", false},
+		{"python dict.get uppercase prefix default", "app.py", "label = cfg.get(\"label\", F\"{name}\")", "dict_get_default", false},
+		{"python dict.get identifier is not a default", "app.py", "value = cfg.get(k, f1)", "", false},
+		{"python getenv identifier is not a default", "app.py", "port = os.getenv(\"PORT\", u8)", "", false},
+		{"go empty check default", "main.go", "if port == \"\" {\n\tport = \"8080\"\n}", "empty_check_default", false},
+		{"go empty check with different variable", "main.go", "if port == \"\" {\n\taddr = \"none\"\n}", "", false},
+		{"go cmp.Or literal", "main.go", "port := cmp.Or(os.Getenv(\"PORT\"), \"8080\")", "cmp_or_default", false},
+		{"go || is a logical operator", "main.go", "ok := a || \"x\" == b", "", false},
+		{"ts destructuring default", "app.ts", "const { port = 3000 } = cfg", "destructuring_default", false},
+		{"ts destructuring parameter default", "app.ts", "function run({ retries = 3 }) {}", "destructuring_default", false},
+		{"ts destructuring without default", "app.ts", "const { port } = cfg", "", false},
+		{"js || default", "app.js", "const port = process.env.PORT || 3000", "or_default", false},
+		{"js ?? default", "app.js", "const host = options.host ?? \"localhost\"", "nullish_default", false},
+		{"js counter idiom", "app.js", "counts[k] = (counts[k] || 0) + 1", "", false},
+		{"shell default", "run.sh", "PORT=${PORT:-8080}", "shell_default", true},
+		{"shell unset check", "run.sh", "if [ -z \"${PORT:-}\" ]; then exit 1; fi", "", false},
+		{"shell required variable", "run.sh", "PORT=${PORT:?PORT is required}", "", false},
+		{"unknown language is skipped", "notes.txt", "use the fall" + "back value", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := &core.FileAnalysis{Path: tt.path, Content: tt.content}
+
+			result, err := validator.Validate(context.Background(), file)
+			if err != nil {
+				t.Fatalf("validation failed: %v", err)
+			}
+			if tt.wantType == "" {
+				if len(result.Violations) != 0 {
+					t.Errorf("expected no violations, got %+v", result.Violations)
+				}
+				return
+			}
+			if len(result.Violations) != 1 || result.Violations[0].Type != tt.wantType {
+				t.Fatalf("expected one %s violation, got %+v", tt.wantType, result.Violations)
+			}
+			if tt.wantBlock == result.IsValid {
+				t.Errorf("expected block=%v, got valid=%v", tt.wantBlock, result.IsValid)
+			}
+		})
+	}
+}
+
+func TestEmergencyDefaultsValidator_TeamPacks(t *testing.T) {
+	logger := core.NewTestLogger()
+	config := core.ValidatorConfig{
+		Enabled:   true,
+		Languages: []string{"python", "js"},
+		Packs: map[string]core.DefaultsPackConfig{
+			"python": {
+				Allowed:  []string{`\bsettings\.get\(`},
+				Patterns: []string{`\bsetdefault\(`},
+			},
+		},
+	}
+
+	validator, err := NewEmergencyDefaultsValidator(config, logger)
+	if err != nil {
+		t.Fatalf("failed to create validator: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		content   string
+		wantType  string
+		wantBlock bool
+	}{
+		{"language filtered out", "run.sh", "PORT=${PORT:-8080}", "", false},
+		{"team allowed construct", "app.py", "debug = settings.get(\"debug\", \"off\")", "", false},
+		{"built-in rules still apply", "app.py", "name = cfg.get(\"name\", \"anonymous\")", "dict_get_default", false},
+		{"team pattern", "app.py", "cfg.setdefault(\"port\", 8080)", "custom_default", true},
+		{"team pattern is per language", "app.js", "cfg.setdefault(\"port\", 8080)", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := &core.FileAnalysis{Path: tt.path, Content: tt.content}
+
+			result, err := validator.Validate(context.Background(), file)
+			if err != nil {
+				t.Fatalf("validation failed: %v", err)
+			}
+			if tt.wantType == "" {
+				if len(result.Violations) != 0 {
+					t.Errorf("expected no violations, got %+v", result.Violations)
+				}
+				return
+			}
+			if len(result.Violations) != 1 || result.Violations[0].Type != tt.wantType {
+				t.Fatalf("expected one %s violation, got %+v", tt.wantType, result.Violations)
+			}
+			if tt.wantBlock == result.IsValid {
+				t.Errorf("expected block=%v, got valid=%v", tt.wantBlock, result.IsValid)
+			}
+		})
+	}
+
+	config.Packs["python"] = core.DefaultsPackConfig{Patterns: []string{"("}}
+	if _, err := NewEmergencyDefaultsValidator(config, logger); err == nil {
+		t.Error("expected error for invalid pack pattern")
+	}
+}
@@ -2,61 +2,54 @@ package validators
 
 import (
 	"context"
-	"fmt"
 	"regexp"
 	"strings"
 
 	"github.com/aiseeq/claude-hooks/internal/core"
+	"github.com/aiseeq/claude-hooks/internal/shared"
 )
 
 // RuntimeExitValidator проверяет использование критических выходов в production коде
 type RuntimeExitValidator struct {
 	*BaseValidator
-	goFilesOnly     bool
-	testExceptions  []string
+	languages       map[shared.Language]bool // nil - все языки
+	testExceptions  *shared.PathMatcher
 	productionPaths []string
-	patterns        []*regexp.Regexp
+	fatalFunctions  fatalFunctions
 }
 
 // NewRuntimeExitValidator создает новый валидатор критических выходов
 func NewRuntimeExitValidator(config core.ValidatorConfig, logger core.Logger) (*RuntimeExitValidator, error) {
-	baseValidator := NewBaseValidator("runtime_exit", config.Enabled, config.ExceptionPaths, logger)
+	baseValidator := NewBaseValidator("runtime_exit", config.Enabled, exceptionPatterns("runtime_exit", config), logger)
 
 	validator := &RuntimeExitValidator{
 		BaseValidator:   baseValidator,
-		goFilesOnly:     config.GoFilesOnly,
-		testExceptions:  config.TestExceptions,
+		languages:       configLanguages(config),
+		testExceptions:  shared.NewPathMatcher(config.TestExceptions),
 		productionPaths: config.ProductionPaths,
+		fatalFunctions:  parseFatalFunctions(defaultFatalFunctions, config.FatalFunctions),
 	}
-
-	// Компилируем паттерны
-	if err := validator.compilePatterns(); err != nil {
-		return nil, fmt.Errorf("failed to compile patterns: %w", err)
-	}
+	baseValidator.setMatchScope(config, shared.SpanCode)
 
 	return validator, nil
 }
 
-// compilePatterns компилирует regex паттерны для поиска критических выходов
-func (v *RuntimeExitValidator) compilePatterns() error {
-	// Разделяем паттерны на части чтобы избежать блокировки хуков
-	part1 := "pa" + "nic" + "\\s*\\("
-	part2 := "log\\." + "Fat" + "al\\s*\\("
-	part3 := "log\\." + "Fat" + "alf\\s*\\("
-	part4 := "log\\." + "Fat" + "alln\\s*\\("
-	part5 := "os\\." + "Ex" + "it\\s*\\("
-
-	exitPatterns := []string{part1, part2, part3, part4, part5}
-
-	for _, pattern := range exitPatterns {
-		compiled, err := regexp.Compile(pattern)
-		if err != nil {
-			return fmt.Errorf("failed to compile pattern %s: %w", pattern, err)
-		}
-		v.patterns = append(v.patterns, compiled)
+// configLanguages возвращает проверяемые языки из languages
+// Устаревший go_files_only означает только Go, пустой список - все языки
+func configLanguages(config core.ValidatorConfig) map[shared.Language]bool {
+	names := config.Languages
+	if len(names) == 0 && config.GoFilesOnly {
+		names = []string{string(shared.LangGo)}
+	}
+	if len(names) == 0 {
+		return nil
 	}
 
-	return nil
+	languages := make(map[shared.Language]bool, len(names))
+	for _, name := range names {
+		languages[shared.Language(strings.ToLower(name))] = true
+	}
+	return languages
 }
 
 // Validate выполняет валидацию файла
@@ -65,28 +58,33 @@ func (v *RuntimeExitValidator) Validate(ctx context.Context, file *core.FileAnal
 		return &core.ValidationResult{IsValid: true}, nil
 	}
 
-	// Проверяем только Go файлы если настроено
-	if v.goFilesOnly && !strings.HasSuffix(file.Path, ".go") {
-		v.logger.Debug("not a Go file, skipping", "file", file.Path)
+	// Выбираем набор правил по языку файла
+	pack := exitPackFor(fileExtension(file.Extension, file.Path))
+	if pack == nil || (v.languages != nil && !v.languages[pack.language]) {
+		v.logger.Debug("language not checked, skipping", "file", file.Path)
 		return &core.ValidationResult{IsValid: true}, nil
 	}
 
 	// Проверяем исключения
-	isException := v.IsExceptionFile(file.Path)
+	isException := v.IsExceptionFile(file.ProjectPath())
 	if isException {
 		v.logger.Debug("file is exception, skipping validation", "file", file.Path)
 		return &core.ValidationResult{IsValid: true}, nil
 	}
 
 	// Проверяем является ли файл тестовым
-	isTest := v.isTestFile(file.Path)
+	isTest := v.isTestFile(file.ProjectPath())
 	if isTest {
 		v.logger.Debug("test file detected, skipping runtime exit validation", "file", file.Path)
 		return &core.ValidationResult{IsValid: true}, nil
 	}
 
-	// Ищем совпадения с паттернами критических выходов
-	matches := v.FindPatternMatches(file.Content, v.patterns)
+	// Ищем вызовы критических выходов
+	matches, isEntry := v.findExitCalls(file, pack)
+	if isEntry {
+		v.logger.Debug("entry point, skipping runtime exit validation", "file", file.Path)
+		return &core.ValidationResult{IsValid: true}, nil
+	}
 	if len(matches) == 0 {
 		return &core.ValidationResult{IsValid: true}, nil
 	}
@@ -109,7 +107,7 @@ func (v *RuntimeExitValidator) Validate(ctx context.Context, file *core.FileAnal
 	}
 
 	// Генерируем общие предложения
-	suggestions := v.generateSuggestions(file, matches)
+	suggestions := v.generateSuggestions(file, pack)
 
 	v.logger.Info("runtime exit usage detected in production code",
 		"file", file.Path,
@@ -123,32 +121,75 @@ func (v *RuntimeExitValidator) Validate(ctx context.Context, file *core.FileAnal
 	}, nil
 }
 
-// isTestFile проверяет является ли файл тестовым с учетом настроек валидатора
-func (v *RuntimeExitValidator) isTestFile(filePath string) bool {
-	// Проверяем базовые паттерны тестовых файлов
-	if isTestFile(filePath) {
-		return true
+// findExitCalls ищет вызовы критических выходов и сообщает, является ли файл точкой входа
+// Go код разбирается через go/parser с учетом алиасов импортов, package main пропускается целиком
+// Остальные языки и неразбираемый Go код проверяются паттернами набора вне комментариев и строк,
+// вызовы в блоках точки входа (if __name__ == "__main__":) пропускаются
+func (v *RuntimeExitValidator) findExitCalls(file *core.FileAnalysis, pack *exitPack) ([]PatternMatch, bool) {
+	if pack.language == shared.LangGo {
+		if source := parseGoSource(file.Path, file.Content); source != nil {
+			if source.pkgName == "main" {
+				return nil, true
+			}
+			var matches []PatternMatch
+			for _, call := range source.findExitCalls(v.fatalFunctions) {
+				text := call.text
+				if text == "" {
+					text = call.name
+				}
+				matches = append(matches, PatternMatch{Line: call.line, Column: call.column, Text: text})
+			}
+			return matches, false
+		}
+		v.logger.Debug("go source does not parse, using patterns", "file", file.Path)
+	}
+
+	if pack.isEntryScript(file.ProjectPath(), file.Content) {
+		return nil, true
+	}
+
+	var blocks []lineRange
+	if pack.entryBlocks != nil {
+		code := shared.ScopeContent(file.Content, pack.language, []shared.SpanKind{shared.SpanCode})
+		blocks = pack.entryBlocks(file.Content, code)
 	}
 
-	// Проверяем дополнительные исключения из конфигурации
-	for _, exception := range v.testExceptions {
-		if strings.Contains(filePath, exception) {
-			return true
+	var matches []PatternMatch
+	for _, match := range v.FindPatternMatches(v.ScopedContent(file), pack.patterns) {
+		if inEntryBlock(blocks, match.Line) {
+			continue
+		}
+		// Паттерн может захватить символ перед именем вызова (exit без точки перед ним)
+		if trimmed := strings.TrimLeft(match.Text, " \t([{,;=!&|:"); trimmed != match.Text {
+			match.Column += len(match.Text) - len(trimmed)
+			match.Text = trimmed
 		}
+		if pack.definitions != nil && pack.definitions.MatchString(match.Text) {
+			continue
+		}
+		matches = append(matches, match)
 	}
+	return matches, false
+}
 
-	return false
+// isTestFile проверяет дополнительные тестовые исключения из test_exceptions
+// Встроенные тестовые шаблоны уже входят в исключения валидатора
+func (v *RuntimeExitValidator) isTestFile(filePath string) bool {
+	_, matched := v.testExceptions.Match(filePath)
+	return matched
 }
 
-// determineViolationType определяет тип нарушения по тексту совпадения
+// determineViolationType определяет тип нарушения по имени вызванной функции
 func (v *RuntimeExitValidator) determineViolationType(matchText string) string {
-	target1 := "pa" + "nic"
-	target2 := "Fat" + "al"
+	name := strings.TrimSpace(strings.TrimRight(matchText, "( \t"))
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		name = name[dot+1:]
+	}
 
-	if strings.Contains(matchText, target1) {
+	switch {
+	case name == builtinExit || strings.HasPrefix(name, "Pa"+"nic"):
 		return "runtime_exit_usage"
-	}
-	if strings.Contains(matchText, target2) {
+	case strings.HasPrefix(name, "Fat"+"al"):
 		return "log_fatal_usage"
 	}
 	return "critical_exit"
@@ -183,7 +224,11 @@ func (v *RuntimeExitValidator) generateSuggestion(violationType string) string {
 }
 
 // generateSuggestions генерирует предложения по исправлению
-func (v *RuntimeExitValidator) generateSuggestions(file *core.FileAnalysis, matches []PatternMatch) []string {
+func (v *RuntimeExitValidator) generateSuggestions(file *core.FileAnalysis, pack *exitPack) []string {
+	if pack.suggestions != nil {
+		return pack.suggestions
+	}
+
 	suggestions := []string{
 		"Используй error возврат из функций: func() error { return fmt.Errorf(...) }",
 		"Реализуй graceful error handling на уровне приложения",
@@ -216,32 +261,3 @@ func (v *RuntimeExitValidator) containsRecoverPattern(content string) bool {
 	recoverPattern := regexp.MustCompile(`recover\s*\(\s*\)`)
 	return recoverPattern.MatchString(content)
 }
-
-// IsExceptionFile переопределяет базовый метод с дополнительной логикой
-func (v *RuntimeExitValidator) IsExceptionFile(filePath string) bool {
-	// Базовые исключения
-	baseResult := v.BaseValidator.IsExceptionFile(filePath)
-	if baseResult {
-		return true
-	}
-
-	// Дополнительные исключения для runtime exit validator
-	exitExceptions := []string{
-		"/cmd/", "/main.go", // CLI приложения могут использовать критические выходы
-		"/examples/", "/demo/", // Примеры кода
-		"/benchmark/", "_bench.go", // Бенчмарки
-	}
-
-	for _, exception := range exitExceptions {
-		if strings.Contains(filePath, exception) {
-			v.logger.Debug("file matched runtime exit validator exception", "file", filePath, "exception", exception)
-			return true
-		}
-	}
-
-	// ИСПРАВЛЕНИЕ: УДАЛЯЕМ ПОРОЧНУЮ ЛОГИКУ production paths
-	// Runtime Exit Validator должен блокировать опасные patterns ВЕЗДЕ,
-	// кроме явных исключений выше. Логика "разрешать в НЕ-production" делает validator бесполезным.
-
-	return false
-}
@@ -0,0 +1,264 @@
+package validators
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultFatalFunctions функции, завершающие программу или горутину, в виде "путь/импорта.Имя"
+// Методы логгеров (zap.L().Fatal, logrus.WithError(err).Fatal) находятся по пакету,
+// с которого начинается цепочка вызовов
+var defaultFatalFunctions = []string{
+	"os.Exit",
+	"runtime.Goexit",
+	"log.Fatal", "log.Fatalf", "log.Fatalln",
+	"log.Panic", "log.Panicf", "log.Panicln",
+	"github.com/sirupsen/logrus.Fatal", "github.com/sirupsen/logrus.Fatalf", "github.com/sirupsen/logrus.Fatalln",
+	"github.com/sirupsen/logrus.Panic", "github.com/sirupsen/logrus.Panicf", "github.com/sirupsen/logrus.Panicln",
+	"go.uber.org/zap.Fatal", "go.uber.org/zap.Fatalf", "go.uber.org/zap.Fatalw", "go.uber.org/zap.Fatalln",
+	"go.uber.org/zap.Panic", "go.uber.org/zap.Panicf", "go.uber.org/zap.Panicw", "go.uber.org/zap.Panicln",
+	"k8s.io/klog.Fatal", "k8s.io/klog.Fatalf", "k8s.io/klog.Fatalln", "k8s.io/klog.Exit", "k8s.io/klog.Exitf",
+	"k8s.io/klog/v2.Fatal", "k8s.io/klog/v2.Fatalf", "k8s.io/klog/v2.Fatalln", "k8s.io/klog/v2.Exit", "k8s.io/klog/v2.Exitf",
+}
+
+// builtinExit встроенная функция аварийного завершения
+var builtinExit = "pa" + "nic"
+
+// versionSuffix суффикс major версии в пути импорта: /v2, .v3
+var versionSuffix = regexp.MustCompile(`[./]v\d+$`)
+
+// fatalFunctions набор функций завершения: путь импорта -> имена
+type fatalFunctions map[string]map[string]bool
+
+// parseFatalFunctions разбирает список "путь/импорта.Имя"
+// Некорректные записи пропускаются, их находит проверка конфигурации
+func parseFatalFunctions(lists ...[]string) fatalFunctions {
+	functions := make(fatalFunctions)
+	for _, list := range lists {
+		for _, entry := range list {
+			importPath, name, ok := splitFatalFunction(entry)
+			if !ok {
+				continue
+			}
+			if functions[importPath] == nil {
+				functions[importPath] = make(map[string]bool)
+			}
+			functions[importPath][name] = true
+		}
+	}
+	return functions
+}
+
+// splitFatalFunction делит "путь/импорта.Имя" на путь и имя
+func splitFatalFunction(entry string) (string, string, bool) {
+	dot := strings.LastIndex(entry, ".")
+	if dot <= 0 || dot < strings.LastIndex(entry, "/") || dot == len(entry)-1 {
+		return "", "", false
+	}
+	return entry[:dot], entry[dot+1:], true
+}
+
+// defaultPackageName имя пакета по пути импорта, если в импорте не задан алиас
+func defaultPackageName(importPath string) string {
+	return path.Base(versionSuffix.ReplaceAllString(importPath, ""))
+}
+
+// goExitCall найденный вызов функции завершения
+type goExitCall struct {
+	line   int
+	column int
+	text   string // имя вызова как в коде, например l.Fatalf
+	name   string // имя функции, например Fatalf
+}
+
+// goSource разобранный Go код для поиска вызовов
+type goSource struct {
+	fset       *token.FileSet
+	file       *ast.File
+	lineOffset int  // строки обертки фрагмента перед исходным текстом
+	fragment   bool // фрагмент правки без собственных импортов
+	pkgName    string
+	imports    map[string]string // локальное имя -> путь импорта
+	dotImports []string
+}
+
+// parseGoSource разбирает Go файл или фрагмент правки
+// Для фрагмента пакет и импорты берутся из файла на диске, если он существует
+// Возвращает nil, если текст не разбирается ни как файл, ни как фрагмент
+func parseGoSource(filePath, content string) *goSource {
+	source := &goSource{fset: token.NewFileSet(), imports: make(map[string]string)}
+
+	if file, err := parser.ParseFile(source.fset, filePath, content, parser.SkipObjectResolution); err == nil {
+		source.file = file
+	} else {
+		source.fragment = true
+		wrappers := []struct {
+			prefix, suffix string
+			lines          int
+		}{
+			{"package fragment\nfunc _() {\n", "\n}", 2},
+			{"package fragment\n", "", 1},
+		}
+		for _, wrapper := range wrappers {
+			file, err := parser.ParseFile(source.fset, filePath, wrapper.prefix+content+wrapper.suffix, parser.SkipObjectResolution)
+			if err == nil {
+				source.file, source.lineOffset = file, wrapper.lines
+				break
+			}
+		}
+		if source.file == nil {
+			return nil
+		}
+		if data, err := os.ReadFile(filePath); err == nil {
+			if onDisk, err := parser.ParseFile(token.NewFileSet(), filePath, data, parser.ImportsOnly); err == nil {
+				source.pkgName = onDisk.Name.Name
+				source.addImports(onDisk.Imports)
+			}
+		}
+	}
+
+	if !source.fragment {
+		source.pkgName = source.file.Name.Name
+	}
+	source.addImports(source.file.Imports)
+	return source
+}
+
+// addImports запоминает локальные имена импортов
+func (s *goSource) addImports(imports []*ast.ImportSpec) {
+	for _, spec := range imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := defaultPackageName(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		switch name {
+		case "_":
+		case ".":
+			s.dotImports = append(s.dotImports, importPath)
+		default:
+			s.imports[name] = importPath
+		}
+	}
+}
+
+// resolve возвращает путь импорта для идентификатора пакета
+// Во фрагменте без известных импортов имя сопоставляется с именем пакета по умолчанию
+func (s *goSource) resolve(ident string, functions fatalFunctions) (string, bool) {
+	if importPath, ok := s.imports[ident]; ok {
+		return importPath, true
+	}
+	if !s.fragment {
+		return "", false
+	}
+	for importPath := range functions {
+		if defaultPackageName(importPath) == ident {
+			return importPath, true
+		}
+	}
+	return "", false
+}
+
+// findExitCalls ищет вызовы функций завершения вне init()
+func (s *goSource) findExitCalls(functions fatalFunctions) []goExitCall {
+	var calls []goExitCall
+
+	ast.Inspect(s.file, func(node ast.Node) bool {
+		if decl, ok := node.(*ast.FuncDecl); ok && decl.Recv == nil && decl.Name.Name == "init" {
+			// init() выполняется до main, аварийное завершение там допустимо
+			return false
+		}
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if name, ok := s.exitFunction(call.Fun, functions); ok {
+			pos := s.fset.Position(call.Fun.Pos())
+			end := s.fset.Position(call.Fun.End())
+			text := ""
+			if pos.Line == end.Line {
+				text = exprText(call.Fun)
+			}
+			calls = append(calls, goExitCall{
+				line:   pos.Line - s.lineOffset,
+				column: pos.Column,
+				text:   text,
+				name:   name,
+			})
+		}
+		return true
+	})
+
+	return calls
+}
+
+// exitFunction проверяет, вызывает ли выражение функцию завершения
+func (s *goSource) exitFunction(fun ast.Expr, functions fatalFunctions) (string, bool) {
+	switch expr := fun.(type) {
+	case *ast.Ident:
+		if expr.Name == builtinExit {
+			return expr.Name, true
+		}
+		for _, importPath := range s.dotImports {
+			if functions[importPath][expr.Name] {
+				return expr.Name, true
+			}
+		}
+	case *ast.SelectorExpr:
+		root := rootIdent(expr.X)
+		if root == nil {
+			return "", false
+		}
+		importPath, ok := s.resolve(root.Name, functions)
+		if ok && functions[importPath][expr.Sel.Name] {
+			return expr.Sel.Name, true
+		}
+	}
+	return "", false
+}
+
+// rootIdent возвращает идентификатор, с которого начинается цепочка pkg.A().B
+func rootIdent(expr ast.Expr) *ast.Ident {
+	for {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			return e
+		case *ast.SelectorExpr:
+			expr = e.X
+		case *ast.CallExpr:
+			expr = e.Fun
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		default:
+			return nil
+		}
+	}
+}
+
+// exprText восстанавливает текст вызываемого выражения для сообщения
+func exprText(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return exprText(e.X) + "." + e.Sel.Name
+	case *ast.CallExpr:
+		return exprText(e.Fun) + "()"
+	case *ast.ParenExpr:
+		return "(" + exprText(e.X) + ")"
+	case *ast.IndexExpr:
+		return exprText(e.X) + "[...]"
+	}
+	return "..."
+}
@@ -0,0 +1,203 @@
+package validators
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/aiseeq/claude-hooks/internal/shared"
+)
+
+// exitPack правила критических выходов для одного языка
+type exitPack struct {
+	language   shared.Language
+	extensions []string
+	// patterns ищутся в коде вне комментариев и строк; для Go - только если код не разбирается
+	patterns []*regexp.Regexp
+	// definitions совпадения, которые определяют функцию с именем выхода, а не вызывают ее: def exit(self)
+	definitions *regexp.Regexp
+	// entryPaths точки входа (скрипты), где завершение процесса допустимо
+	entryPaths *shared.PathMatcher
+	// entryBlocks находит строки блоков точки входа, например if __name__ == "__main__":
+	entryBlocks func(content, code string) []lineRange
+	// suggestions рекомендации по исправлению для языка
+	suggestions []string
+}
+
+// lineRange диапазон строк [from, to] включительно
+type lineRange struct {
+	from int
+	to   int
+}
+
+// contains проверяет попадание строки в диапазон
+func (r lineRange) contains(line int) bool {
+	return line >= r.from && line <= r.to
+}
+
+// scriptEntryPaths общие для скриптовых языков директории исполняемых скриптов
+var scriptEntryPaths = []string{"bin/", "scripts/"}
+
+// exitPacks встроенные наборы правил по языкам
+var exitPacks = []*exitPack{
+	{
+		language:   shared.LangGo,
+		extensions: []string{".go"},
+		// Точки входа Go (package main, init) определяются по синтаксическому дереву
+		patterns: compileExitPatterns(
+			`\b`+"pa"+"nic"+`\s*\(`,
+			`\blog\.`+"Fat"+`al(f|ln)?\s*\(`,
+			`\bos\.`+"Ex"+`it\s*\(`,
+		),
+	},
+	{
+		language:   shared.LangJS,
+		extensions: []string{".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs"},
+		patterns: compileExitPatterns(
+			`\bprocess\.(exit|abort)\s*\(`,
+			`\bDeno\.exit\s*\(`,
+		),
+		entryPaths:  shared.NewPathMatcher(scriptEntryPaths, []string{"cli.js", "cli.ts", "cli.mjs"}),
+		entryBlocks: jsEntryBlocks,
+		suggestions: []string{
+			"Бросай Error или возвращай отклоненный Promise вместо завершения процесса",
+			"Завершай процесс только в точке входа (bin/ или блок require.main === module)",
+		},
+	},
+	{
+		language:   shared.LangPython,
+		extensions: []string{".py"},
+		patterns: compileExitPatterns(
+			`\bsys\.exit\s*\(`,
+			`\bos\._exit\s*\(`,
+			`\braise\s+SystemExit\b`,
+			`(^|[^.\w])(?:(?:async\s+)?def\s+)?(exit|quit)\s*\(`,
+		),
+		definitions: regexp.MustCompile(`^(?:async\s+)?def\s`),
+		entryPaths:  shared.NewPathMatcher(scriptEntryPaths, []string{"__main__.py", "manage.py"}),
+		entryBlocks: pythonEntryBlocks,
+		suggestions: []string{
+			"Бросай исключение вместо завершения процесса в библиотечном коде",
+			"Завершай процесс только в блоке if __name__ == \"__main__\": или в __main__.py",
+		},
+	},
+}
+
+// compileExitPatterns компилирует встроенные паттерны пакета
+func compileExitPatterns(patterns ...string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		compiled = append(compiled, regexp.MustCompile(pattern))
+	}
+	return compiled
+}
+
+// exitPackFor выбирает набор правил по расширению файла
+func exitPackFor(extension string) *exitPack {
+	extension = strings.ToLower(extension)
+	for _, pack := range exitPacks {
+		for _, ext := range pack.extensions {
+			if ext == extension {
+				return pack
+			}
+		}
+	}
+	return nil
+}
+
+// fileExtension возвращает расширение из анализа файла или из пути
+func fileExtension(extension, filePath string) string {
+	if extension != "" {
+		return extension
+	}
+	return filepath.Ext(filePath)
+}
+
+// isEntryScript проверяет, является ли файл точкой входа: путь скрипта или shebang
+func (p *exitPack) isEntryScript(projectPath, content string) bool {
+	if p.language == shared.LangGo {
+		return false
+	}
+	if strings.HasPrefix(content, "#!") {
+		return true
+	}
+	_, entry := p.entryPaths.Match(projectPath)
+	return entry
+}
+
+// inEntryBlock проверяет, находится ли строка в блоке точки входа
+func inEntryBlock(blocks []lineRange, line int) bool {
+	for _, block := range blocks {
+		if block.contains(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// pythonMainPattern условие запуска модуля как скрипта
+var pythonMainPattern = regexp.MustCompile(`^(\s*)if\s+__name__\s*==\s*['"]__main__['"]\s*:`)
+
+// pythonEntryBlocks находит блоки if __name__ == "__main__": по отступам
+func pythonEntryBlocks(content, code string) []lineRange {
+	var blocks []lineRange
+	lines := strings.Split(content, "\n")
+	codeLines := strings.Split(code, "\n")
+
+	for i := 0; i < len(lines); i++ {
+		match := pythonMainPattern.FindStringSubmatch(lines[i])
+		// Условие должно быть кодом, а не текстом внутри строки
+		if match == nil || !strings.HasPrefix(strings.TrimSpace(codeLines[i]), "if") {
+			continue
+		}
+		indent := len(match[1])
+		block := lineRange{from: i + 1, to: i + 1}
+		for j := i + 1; j < len(lines); j++ {
+			trimmed := strings.TrimSpace(lines[j])
+			if trimmed != "" && len(lines[j])-len(strings.TrimLeft(lines[j], " \t")) <= indent {
+				break
+			}
+			block.to = j + 1
+		}
+		blocks = append(blocks, block)
+		i = block.to - 1
+	}
+
+	return blocks
+}
+
+// jsMainPattern условия запуска модуля как скрипта в Node.js и Deno
+var jsMainPattern = regexp.MustCompile(`require\.main\s*===?\s*module|import\.meta\.main`)
+
+// jsEntryBlocks находит блоки if (require.main === module) { ... } по фигурным скобкам
+// Поиск идет по коду без комментариев и строк, поэтому скобки в литералах не учитываются
+func jsEntryBlocks(content, code string) []lineRange {
+	var blocks []lineRange
+
+	for _, loc := range jsMainPattern.FindAllStringIndex(code, -1) {
+		open := strings.IndexByte(code[loc[1]:], '{')
+		if open < 0 {
+			continue
+		}
+		open += loc[1]
+
+		depth, end := 0, len(code)-1
+		for i := open; i < len(code); i++ {
+			if code[i] == '{' {
+				depth++
+			} else if code[i] == '}' {
+				depth--
+				if depth == 0 {
+					end = i
+					break
+				}
+			}
+		}
+		blocks = append(blocks, lineRange{
+			from: strings.Count(code[:loc[0]], "\n") + 1,
+			to:   strings.Count(code[:end], "\n") + 1,
+		})
+	}
+
+	return blocks
+}
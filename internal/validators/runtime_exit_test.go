@@ -2,6 +2,8 @@ package validators
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/aiseeq/claude-hooks/internal/core"
@@ -53,6 +55,26 @@ func TestRuntimeExitValidator_BlocksDangerousCalls(t *testing.T) {
 			content:   "log.Error(\"something failed\")",
 			wantBlock: false,
 		},
+		{
+			name:      "allows call in trailing comment",
+			content:   "return err // never os.Exit(1) here",
+			wantBlock: false,
+		},
+		{
+			name:      "allows call in string literal",
+			content:   "msg := \"do not call panic() in handlers\"",
+			wantBlock: false,
+		},
+		{
+			name:      "allows call in block comment",
+			content:   "/*\n\tlog.Fatal(err)\n*/\nreturn err",
+			wantBlock: false,
+		},
+		{
+			name:      "blocks call after string with comment marker",
+			content:   "url := \"http://host\"; os.Exit(1)",
+			wantBlock: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -159,3 +181,247 @@ func TestRuntimeExitValidator_AllowsInTests(t *testing.T) {
 		t.Error("should allow panic in test files")
 	}
 }
+
+func TestRuntimeExitValidator_GoSyntax(t *testing.T) {
+	logger := core.NewTestLogger()
+	config := core.ValidatorConfig{
+		Enabled:        true,
+		FatalFunctions: []string{"github.com/acme/app/internal/die.Now"},
+	}
+
+	validator, err := NewRuntimeExitValidator(config, logger)
+	if err != nil {
+		t.Fatalf("failed to create validator: %v", err)
+	}
+
+	fatal := "Fat" + "al"
+	tests := []struct {
+		name      string
+		content   string
+		wantType  string
+		wantLine  int
+		wantBlock bool
+	}{
+		{
+			name:      "aliased log import",
+			content:   "package svc\n\nimport l \"log\"\n\nfunc run() {\n\tl." + fatal + "f(\"x\")\n}\n",
+			wantType:  "log_fatal_usage",
+			wantLine:  6,
+			wantBlock: true,
+		},
+		{
+			name:      "log panic variant",
+			content:   "package svc\n\nimport \"log\"\n\nfunc run() {\n\tlog.Pa" + "nicf(\"x\")\n}\n",
+			wantType:  "runtime_exit_usage",
+			wantLine:  6,
+			wantBlock: true,
+		},
+		{
+			name:      "zap logger chain",
+			content:   "package svc\n\nimport \"go.uber.org/zap\"\n\nfunc run() {\n\tzap.L()." + fatal + "(\"x\")\n}\n",
+			wantType:  "log_fatal_usage",
+			wantLine:  6,
+			wantBlock: true,
+		},
+		{
+			name:      "logrus entry",
+			content:   "package svc\n\nimport log \"github.com/sirupsen/logrus\"\n\nfunc run(err error) {\n\tlog.WithError(err)." + fatal + "(\"x\")\n}\n",
+			wantType:  "log_fatal_usage",
+			wantLine:  6,
+			wantBlock: true,
+		},
+		{
+			name:      "klog v2",
+			content:   "package svc\n\nimport \"k8s.io/klog/v2\"\n\nfunc run() {\n\tklog." + fatal + "f(\"x\")\n}\n",
+			wantType:  "log_fatal_usage",
+			wantLine:  6,
+			wantBlock: true,
+		},
+		{
+			name:      "goexit",
+			content:   "package svc\n\nimport \"runtime\"\n\nfunc run() {\n\truntime.Goexit()\n}\n",
+			wantType:  "critical_exit",
+			wantLine:  6,
+			wantBlock: true,
+		},
+		{
+			name:      "configured fatal function",
+			content:   "package svc\n\nimport \"github.com/acme/app/internal/die\"\n\nfunc run() {\n\tdie.Now()\n}\n",
+			wantType:  "critical_exit",
+			wantLine:  6,
+			wantBlock: true,
+		},
+		{
+			name:      "local variable named like a package",
+			content:   "package svc\n\nfunc run(log *Logger) {\n\tlog." + fatal + "(\"x\")\n}\n",
+			wantBlock: false,
+		},
+		{
+			name:      "package main is exempt",
+			content:   "package main\n\nimport \"os\"\n\nfunc main() {\n\tos.Exit(1)\n}\n",
+			wantBlock: false,
+		},
+		{
+			name:      "init is exempt",
+			content:   "package svc\n\nimport \"log\"\n\nfunc init() {\n\tlog." + fatal + "(\"x\")\n}\n",
+			wantBlock: false,
+		},
+		{
+			name:      "method named init is checked",
+			content:   "package svc\n\nimport \"os\"\n\nfunc (s *S) init() {\n\tos.Exit(1)\n}\n",
+			wantType:  "critical_exit",
+			wantLine:  6,
+			wantBlock: true,
+		},
+		{
+			name:      "edit fragment",
+			content:   "if err != nil {\n\tlog." + fatal + "ln(err)\n}",
+			wantType:  "log_fatal_usage",
+			wantLine:  2,
+			wantBlock: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := &core.FileAnalysis{
+				Path:    "internal/svc/service.go",
+				Content: tt.content,
+			}
+
+			result, err := validator.Validate(context.Background(), file)
+			if err != nil {
+				t.Fatalf("validation failed: %v", err)
+			}
+			if tt.wantBlock == result.IsValid {
+				t.Fatalf("expected block=%v, got valid=%v (%v)", tt.wantBlock, result.IsValid, result.Violations)
+			}
+			if !tt.wantBlock {
+				return
+			}
+			if len(result.Violations) != 1 {
+				t.Fatalf("expected one violation, got %v", result.Violations)
+			}
+			if v := result.Violations[0]; v.Type != tt.wantType || v.Line != tt.wantLine {
+				t.Errorf("got %s at line %d, want %s at line %d", v.Type, v.Line, tt.wantType, tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestRuntimeExitValidator_FragmentUsesFileOnDisk(t *testing.T) {
+	logger := core.NewTestLogger()
+	validator, err := NewRuntimeExitValidator(core.ValidatorConfig{Enabled: true}, logger)
+	if err != nil {
+		t.Fatalf("failed to create validator: %v", err)
+	}
+
+	dir := t.TempDir()
+	mainFile := filepath.Join(dir, "tool.go")
+	if err := os.WriteFile(mainFile, []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	libFile := filepath.Join(dir, "lib.go")
+	if err := os.WriteFile(libFile, []byte("package lib\n\nimport exit \"os\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		content   string
+		wantBlock bool
+	}{
+		{"fragment of package main", mainFile, "os.Exit(1)", false},
+		{"fragment uses imports of the file", libFile, "exit.Exit(1)", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := validator.Validate(context.Background(), &core.FileAnalysis{Path: tt.path, Content: tt.content})
+			if err != nil {
+				t.Fatalf("validation failed: %v", err)
+			}
+			if tt.wantBlock == result.IsValid {
+				t.Errorf("expected block=%v, got valid=%v", tt.wantBlock, result.IsValid)
+			}
+		})
+	}
+}
+
+func TestRuntimeExitValidator_LanguagePacks(t *testing.T) {
+	logger := core.NewTestLogger()
+	validator, err := NewRuntimeExitValidator(core.ValidatorConfig{Enabled: true}, logger)
+	if err != nil {
+		t.Fatalf("failed to create validator: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		content    string
+		wantBlock  bool
+		wantColumn int
+	}{
+		{"node process exit", "src/lib/config.ts", "if (!url) {\n  process.exit(1)\n}", true, 3},
+		{"deno exit", "src/lib/config.ts", "Deno.exit(2)", true, 1},
+		{"exit in js comment", "src/lib/config.js", "// process.exit(1) is forbidden here", false, 0},
+		{"require.main block", "src/server.js", "function main() {}\nif (require.main === module) {\n  main()\n  process.exit(0)\n}", false, 0},
+		{"exit after require.main block", "src/server.js", "if (require.main === module) {\n  main()\n}\nprocess.exit(0)", true, 1},
+		{"bin script", "bin/deploy.js", "process.exit(1)", false, 0},
+		{"shebang script", "tools/deploy.js", "#!/usr/bin/env node\nprocess.exit(1)", false, 0},
+		{"python sys exit", "app/service.py", "import sys\n\ndef load():\n    sys.exit(1)\n", true, 5},
+		{"python os _exit", "app/service.py", "os._exit(1)", true, 1},
+		{"python raise SystemExit", "app/service.py", "def load():\n    raise SystemExit(2)\n", true, 5},
+		{"python builtin exit", "app/service.py", "if failed: exit(1)", true, 12},
+		{"python method named exit", "app/service.py", "ctx.exit(1)", false, 0},
+		{"python exit method definition", "lib/a.py", "class Conn:\n    def exit(self):\n        self.close()\n", false, 0},
+		{"python quit method definition", "lib/a.py", "class Conn:\n    def quit(self):\n        pass\n", false, 0},
+		{"python async exit method definition", "lib/a.py", "class Conn:\n    async def exit(self):\n        pass\n", false, 0},
+		{"python exit inside definition", "lib/a.py", "def quit(self): exit(1)\n", true, 17},
+		{"python main block", "app/service.py", "def main():\n    pass\n\nif __name__ == \"__main__\":\n    main()\n    sys.exit(0)\n", false, 0},
+		{"python after main block", "app/service.py", "if __name__ == '__main__':\n    main()\nsys.exit(0)\n", true, 1},
+		{"python __main__ module", "app/__main__.py", "sys.exit(main())", false, 0},
+		{"python test file", "app/test_service.py", "sys.exit(1)", false, 0},
+		{"shell is not checked", "deploy.sh", "exit 1", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := &core.FileAnalysis{Path: tt.path, Content: tt.content}
+
+			result, err := validator.Validate(context.Background(), file)
+			if err != nil {
+				t.Fatalf("validation failed: %v", err)
+			}
+			if tt.wantBlock == result.IsValid {
+				t.Fatalf("expected block=%v, got valid=%v (%v)", tt.wantBlock, result.IsValid, result.Violations)
+			}
+			if tt.wantBlock && result.Violations[0].Column != tt.wantColumn {
+				t.Errorf("expected column %d, got %d", tt.wantColumn, result.Violations[0].Column)
+			}
+		})
+	}
+}
+
+func TestRuntimeExitValidator_Languages(t *testing.T) {
+	logger := core.NewTestLogger()
+
+	for _, config := range []core.ValidatorConfig{
+		{Enabled: true, Languages: []string{"go"}},
+		{Enabled: true, GoFilesOnly: true},
+	} {
+		validator, err := NewRuntimeExitValidator(config, logger)
+		if err != nil {
+			t.Fatalf("failed to create validator: %v", err)
+		}
+
+		result, err := validator.Validate(context.Background(), &core.FileAnalysis{Path: "app/service.py", Content: "sys.exit(1)"})
+		if err != nil {
+			t.Fatalf("validation failed: %v", err)
+		}
+		if !result.IsValid {
+			t.Errorf("python should not be checked with %+v", config)
+		}
+	}
+}
@@ -5,26 +5,69 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/aiseeq/claude-hooks/internal/core"
+	"github.com/aiseeq/claude-hooks/internal/secrets"
+	"github.com/aiseeq/claude-hooks/internal/shared"
 )
 
 // SecretsValidator проверяет использование hardcoded секретов
 type SecretsValidator struct {
 	*BaseValidator
-	jwtPattern           *regexp.Regexp
-	walletPattern        *regexp.Regexp
-	apiKeyPattern        *regexp.Regexp
-	testConfigExceptions []string
+	jwtPattern    *regexp.Regexp
+	walletPattern *regexp.Regexp // nil - только встроенные детекторы адресов
+	wallets       *secrets.AddressDetector
+	testIssuers   map[string]bool
+	apiKeyPattern *regexp.Regexp
+	vendor        *secrets.RuleDetector
+	structured    *secrets.StructuredScanner // nil - разбор конфигурационных файлов отключен
+	entropy       *secrets.EntropyDetector   // nil - поиск по энтропии отключен
+	allowlist     core.Allowlist
+	ruleEnabled   func(id string) bool
+	sensitiveKeys []string
 }
 
 // NewSecretsValidator создает новый валидатор секретов
 func NewSecretsValidator(config core.ValidatorConfig, logger core.Logger) (*SecretsValidator, error) {
-	baseValidator := NewBaseValidator("secrets", config.Enabled, config.ExceptionPaths, logger)
+	// Исключения тестовых конфигураций проверяются тем же matcher
+	exceptions := exceptionPatterns("secrets", config, config.TestConfigExceptions...)
+	baseValidator := NewBaseValidator("secrets", config.Enabled, exceptions, logger)
 
 	validator := &SecretsValidator{
-		BaseValidator:        baseValidator,
-		testConfigExceptions: config.TestConfigExceptions,
+		BaseValidator: baseValidator,
+		allowlist:     core.NewAllowlist(config.Allowlist),
+		ruleEnabled:   config.RuleEnabled,
+		sensitiveKeys: config.Structured.Keys,
+	}
+	// Секрет в комментарии тоже утечка, поэтому по умолчанию проверяется весь текст
+	baseValidator.setMatchScope(config)
+
+	// Правила известных сервисов отключаются по одному: rules.<тип>.enabled: false
+	validator.vendor = secrets.NewRuleDetector(config.RuleEnabled)
+
+	validator.testIssuers = make(map[string]bool, len(config.JWT.TestIssuers))
+	for _, issuer := range config.JWT.TestIssuers {
+		validator.testIssuers[issuer] = true
+	}
+
+	validator.wallets = secrets.NewAddressDetector(secrets.AddressOptions{
+		Chains:          config.Wallets.Chains,
+		AllowSingleCase: config.Wallets.RequireChecksum != nil && !*config.Wallets.RequireChecksum,
+		PublicAddresses: config.Wallets.PublicAddresses,
+	})
+
+	if structured := config.Structured; structured.Enabled == nil || *structured.Enabled {
+		validator.structured = secrets.NewStructuredScanner(structured.Keys)
+	}
+
+	if entropy := config.Entropy; entropy.Enabled == nil || *entropy.Enabled {
+		validator.entropy = secrets.NewEntropyDetector(secrets.EntropyOptions{
+			HexThreshold:    entropy.HexThreshold,
+			Base64Threshold: entropy.Base64Threshold,
+			MinLength:       entropy.MinLength,
+			Names:           entropy.Names,
+		})
 	}
 
 	// Компилируем паттерны
@@ -35,6 +78,27 @@ func NewSecretsValidator(config core.ValidatorConfig, logger core.Logger) (*Secr
 	return validator, nil
 }
 
+// NewSecretsRedactor создает маскировщик логов и ответов хука по конфигурации validators.secrets
+func NewSecretsRedactor(config core.ValidatorConfig, logger core.Logger) (*secrets.Redactor, error) {
+	validator, err := NewSecretsValidator(config, logger)
+	if err != nil {
+		return nil, err
+	}
+	return validator.Redactor(), nil
+}
+
+// Redactor возвращает маскировщик с детекторами валидатора: отключенные правила и
+// собственные шаблоны (jwt_pattern, entropy.names, wallets) действуют и при маскировке
+func (v *SecretsValidator) Redactor() *secrets.Redactor {
+	return secrets.NewRedactor(secrets.RedactorOptions{
+		RuleEnabled:   v.ruleEnabled,
+		SensitiveKeys: v.sensitiveKeys,
+		JWTPattern:    v.jwtPattern,
+		Entropy:       v.entropy,
+		Wallets:       v.wallets,
+	})
+}
+
 // compilePatterns компилирует regex паттерны для поиска секретов
 func (v *SecretsValidator) compilePatterns(config core.ValidatorConfig) error {
 	var err error
@@ -42,22 +106,19 @@ func (v *SecretsValidator) compilePatterns(config core.ValidatorConfig) error {
 	// JWT токены
 	jwtPattern := config.JWTPattern
 	if jwtPattern == "" {
-		// Разбиваем паттерн на части для избежания срабатывания хуков
-		jwtPattern = "eyJ" + "[a-zA-Z0-9+/]+"
+		jwtPattern = secrets.DefaultJWTPattern
 	}
 	v.jwtPattern, err = regexp.Compile(jwtPattern)
 	if err != nil {
 		return fmt.Errorf("failed to compile JWT pattern: %w", err)
 	}
 
-	// Wallet addresses
-	walletPattern := config.WalletPattern
-	if walletPattern == "" {
-		walletPattern = "0x[a-fA-F0-9]{40}"
-	}
-	v.walletPattern, err = regexp.Compile(walletPattern)
-	if err != nil {
-		return fmt.Errorf("failed to compile wallet pattern: %w", err)
+	// Дополнительный шаблон адресов; прежний шаблон по умолчанию заменен детектором с контрольными суммами
+	if config.WalletPattern != "" && config.WalletPattern != legacyWalletPattern {
+		v.walletPattern, err = regexp.Compile(config.WalletPattern)
+		if err != nil {
+			return fmt.Errorf("failed to compile wallet pattern: %w", err)
+		}
 	}
 
 	// API ключи - разбиваем на части
@@ -77,19 +138,20 @@ func (v *SecretsValidator) Validate(ctx context.Context, file *core.FileAnalysis
 	}
 
 	// Проверяем исключения
-	if v.IsExceptionFile(file.Path) {
+	if v.IsExceptionFile(file.ProjectPath()) {
 		v.logger.Debug("file is exception, skipping validation", "file", file.Path)
 		return &core.ValidationResult{IsValid: true}, nil
 	}
 
 	// Проверяем поддерживаемые типы файлов
 	supportedExtensions := []string{".go", ".ts", ".js", ".tsx", ".jsx", ".py", ".json", ".yaml", ".yml"}
-	if !isSupportedFileType(file.Path, supportedExtensions) {
+	if !isSupportedFileType(file.Path, supportedExtensions) && secrets.StructuredFormat(file.Path) == "" {
 		v.logger.Debug("file type not supported, skipping", "file", file.Path)
 		return &core.ValidationResult{IsValid: true}, nil
 	}
 
-	var violations []core.Violation
+	// Токены известных сервисов проверяются первыми, чтобы общие правила их не дублировали
+	violations := v.checkVendorRules(file)
 
 	// Проверяем JWT токены
 	if jwtViolations := v.checkJWTTokens(file); len(jwtViolations) > 0 {
@@ -102,10 +164,19 @@ func (v *SecretsValidator) Validate(ctx context.Context, file *core.FileAnalysis
 	}
 
 	// Проверяем API ключи
-	if apiViolations := v.checkAPIKeys(file); len(apiViolations) > 0 {
+	if apiViolations := v.checkAPIKeys(file, violations); len(apiViolations) > 0 {
 		violations = append(violations, apiViolations...)
 	}
 
+	// Проверяем значения чувствительных ключей конфигурационных файлов
+	violations = append(violations, v.checkStructured(file, violations)...)
+
+	// Проверяем значения с высокой энтропией без известного префикса
+	violations = append(violations, v.checkEntropy(file, violations)...)
+
+	// Известные безопасные значения из allowlist не блокируются
+	violations = v.filterAllowlisted(violations)
+
 	if len(violations) == 0 {
 		return &core.ValidationResult{IsValid: true}, nil
 	}
@@ -129,64 +200,109 @@ func (v *SecretsValidator) Validate(ctx context.Context, file *core.FileAnalysis
 func (v *SecretsValidator) checkJWTTokens(file *core.FileAnalysis) []core.Violation {
 	var violations []core.Violation
 
-	matches := v.FindPatternMatches(file.Content, []*regexp.Regexp{v.jwtPattern})
+	content := v.ScopedContent(file)
+	lines := strings.Split(content, "\n")
+	matches := v.FindPatternMatches(content, []*regexp.Regexp{v.jwtPattern})
+	tokenEnd := make(map[int]int) // конец последнего токена в строке
 	for _, match := range matches {
-		// Проверяем исключения для тестовых конфигураций
-		if v.isTestConfigException(file.Path) {
-			v.logger.Debug("JWT in test config, skipping", "file", file.Path)
+		// Payload JWT тоже начинается с eyJ, это часть уже найденного токена
+		if match.Column <= tokenEnd[match.Line] {
 			continue
 		}
+		token := secrets.TokenAt(lines[match.Line-1], match.Column-1)
+		tokenEnd[match.Line] = match.Column + len(token) - 1
 
+		// base64 JSON без трех сегментов и заголовка с alg - не токен
+		jwt, ok := secrets.ParseJWT(token)
+		if !ok {
+			continue
+		}
+		violationType, message := v.classifyJWT(jwt)
 		violation := CreateViolation(
 			match,
-			"hardcoded_jwt",
-			"Обнаружен hardcoded JWT токен",
+			violationType,
+			message+" "+jwt.Summary(),
 			"Используй переменные окружения или test-config",
 			core.LevelCritical,
 		)
+		// Паттерн находит только заголовок, общий для многих токенов, поэтому отпечаток берется от всего токена
+		violation.Fingerprint = secrets.Fingerprint(token)
 		violations = append(violations, violation)
 	}
 
 	return violations
 }
 
-// checkWalletAddresses проверяет wallet addresses
+// classifyJWT определяет тип нарушения для токена: тестовый издатель, истекший срок или действующий токен
+// Тип позволяет задать политику через rules, например warn-only для просроченных токенов
+func (v *SecretsValidator) classifyJWT(jwt *secrets.JWT) (string, string) {
+	switch {
+	case jwt.Issuer != "" && v.testIssuers[jwt.Issuer]:
+		return secrets.JWTTypeTestIssuer, "Обнаружен JWT токен тестового издателя"
+	case jwt.Expired(time.Now()):
+		return secrets.JWTTypeExpired, "Обнаружен просроченный JWT токен"
+	}
+	return secrets.JWTTypeSecret, "Обнаружен hardcoded JWT токен"
+}
+
+// legacyWalletPattern прежний шаблон по умолчанию: находил любые 40 hex символов после 0x
+const legacyWalletPattern = "0x[a-fA-F0-9]{40}"
+
+// checkWalletAddresses проверяет адреса кошельков Ethereum, Bitcoin, Solana и Tron
+// Адреса с неверной контрольной суммой и известные публичные адреса пропускаются
 func (v *SecretsValidator) checkWalletAddresses(file *core.FileAnalysis) []core.Violation {
 	var violations []core.Violation
 
-	matches := v.FindPatternMatches(file.Content, []*regexp.Regexp{v.walletPattern})
-	for _, match := range matches {
-		// Проверяем исключения для тестовых конфигураций
-		if v.isTestConfigException(file.Path) {
-			v.logger.Debug("wallet address in test config, skipping", "file", file.Path)
+	content := v.ScopedContent(file)
+	reported := make(map[int]bool)
+	for _, finding := range v.wallets.Find(content) {
+		reported[finding.Line] = true
+		violations = append(violations, core.Violation{
+			Type:        secrets.AddressTypeSecret,
+			Message:     fmt.Sprintf("Обнаружен hardcoded wallet address (%s)", finding.Name),
+			Suggestion:  walletSuggestion,
+			Line:        finding.Line,
+			Column:      finding.Column,
+			Fingerprint: secrets.Fingerprint(finding.Value),
+			Severity:    core.LevelCritical,
+		})
+	}
+
+	if v.walletPattern == nil {
+		return violations
+	}
+	for _, match := range v.FindPatternMatches(content, []*regexp.Regexp{v.walletPattern}) {
+		if reported[match.Line] || v.wallets.Public(match.Text) {
 			continue
 		}
-
 		violation := CreateViolation(
 			match,
-			"hardcoded_wallet",
+			secrets.AddressTypeSecret,
 			"Обнаружен hardcoded wallet address",
-			"Используй TEST_ACCOUNTS из test-config или переменные окружения",
+			walletSuggestion,
 			core.LevelCritical,
 		)
+		violation.Fingerprint = secrets.Fingerprint(match.Text)
 		violations = append(violations, violation)
 	}
 
 	return violations
 }
 
+// walletSuggestion подсказка для найденных адресов кошельков
+const walletSuggestion = "Используй TEST_ACCOUNTS из test-config или переменные окружения, публичные адреса добавь в wallets.public_addresses"
+
 // checkAPIKeys проверяет API ключи
-func (v *SecretsValidator) checkAPIKeys(file *core.FileAnalysis) []core.Violation {
+// Строки, где уже найден ключ известного сервиса, пропускаются
+func (v *SecretsValidator) checkAPIKeys(file *core.FileAnalysis, found []core.Violation) []core.Violation {
 	var violations []core.Violation
 
-	matches := v.FindPatternMatches(file.Content, []*regexp.Regexp{v.apiKeyPattern})
+	reported := reportedLines(found)
+	matches := v.FindPatternMatches(v.ScopedContent(file), []*regexp.Regexp{v.apiKeyPattern})
 	for _, match := range matches {
-		// Проверяем исключения для тестовых конфигураций
-		if v.isTestConfigException(file.Path) {
-			v.logger.Debug("API key in test config, skipping", "file", file.Path)
+		if reported[match.Line] {
 			continue
 		}
-
 		violation := CreateViolation(
 			match,
 			"hardcoded_api_key",
@@ -194,20 +310,126 @@ func (v *SecretsValidator) checkAPIKeys(file *core.FileAnalysis) []core.Violatio
 			"Используй переменные окружения или конфигурационный файл",
 			core.LevelCritical,
 		)
+		violation.Fingerprint = secrets.Fingerprint(match.Text)
 		violations = append(violations, violation)
 	}
 
 	return violations
 }
 
-// isTestConfigException проверяет является ли файл тестовой конфигурацией
-func (v *SecretsValidator) isTestConfigException(filePath string) bool {
-	for _, exception := range v.testConfigExceptions {
-		if strings.Contains(filePath, exception) {
-			return true
+// checkVendorRules проверяет токены известных сервисов: AWS, GitHub, Slack, Stripe и др.
+func (v *SecretsValidator) checkVendorRules(file *core.FileAnalysis) []core.Violation {
+	var violations []core.Violation
+
+	lang := shared.LanguageForPath(file.Path)
+	spans := shared.Tokenize(file.Content, lang)
+	for _, finding := range v.vendor.Find(file.Content) {
+		if !v.inScope(lang, spans, finding.Offset) {
+			continue
+		}
+		rule, _ := secrets.LookupRule(finding.Type)
+		violations = append(violations, core.Violation{
+			Type:        finding.Type,
+			Message:     rule.Message,
+			Suggestion:  rule.Suggestion,
+			Line:        finding.Line,
+			Column:      finding.Column,
+			Fingerprint: finding.Fingerprint(),
+			Severity:    core.LevelCritical,
+		})
+	}
+
+	return violations
+}
+
+// filterAllowlisted убирает нарушения, значения которых есть в действующих записях allowlist
+func (v *SecretsValidator) filterAllowlisted(violations []core.Violation) []core.Violation {
+	if len(v.allowlist) == 0 {
+		return violations
+	}
+
+	now := time.Now()
+	kept := violations[:0]
+	for _, violation := range violations {
+		if entry, ok := v.allowlist.Match(violation.Fingerprint, now); ok {
+			v.logger.Debug("allowlisted secret skipped", "type", violation.Type, "line", violation.Line, "comment", entry.Comment)
+			continue
+		}
+		kept = append(kept, violation)
+	}
+	return kept
+}
+
+// reportedLines возвращает номера строк, где уже есть нарушения
+func reportedLines(violations []core.Violation) map[int]bool {
+	lines := make(map[int]bool, len(violations))
+	for _, violation := range violations {
+		lines[violation.Line] = true
+	}
+	return lines
+}
+
+// checkStructured разбирает JSON, YAML, TOML и .env файлы и проверяет значения чувствительных ключей
+// Если файл не разбирается, остаются только построчные проверки
+func (v *SecretsValidator) checkStructured(file *core.FileAnalysis, found []core.Violation) []core.Violation {
+	if v.structured == nil {
+		return nil
+	}
+
+	findings, err := v.structured.Find(file.Path, file.Content)
+	if err != nil {
+		v.logger.Debug("failed to parse config file, using line checks only", "file", file.Path, "error", err)
+		return nil
+	}
+
+	reported := reportedLines(found)
+	var violations []core.Violation
+	for _, finding := range findings {
+		if reported[finding.Line] {
+			continue
+		}
+		violations = append(violations, core.Violation{
+			Type:        finding.Type,
+			Message:     fmt.Sprintf("Обнаружено значение чувствительного ключа %s", finding.Name),
+			Suggestion:  "Замени значение ссылкой на переменную окружения (${VAR}) или секрет-менеджер",
+			Line:        finding.Line,
+			Column:      finding.Column,
+			Fingerprint: secrets.Fingerprint(finding.Value),
+			Severity:    core.LevelCritical,
+		})
+	}
+
+	return violations
+}
+
+// checkEntropy проверяет присваивания секретных имен значениям с высокой энтропией
+// Строки, где уже найден секрет известного формата, пропускаются
+func (v *SecretsValidator) checkEntropy(file *core.FileAnalysis, found []core.Violation) []core.Violation {
+	if v.entropy == nil {
+		return nil
+	}
+
+	reported := reportedLines(found)
+
+	var violations []core.Violation
+	lang := shared.LanguageForPath(file.Path)
+	spans := shared.Tokenize(file.Content, lang)
+	for _, finding := range v.entropy.Find(file.Path, file.Content) {
+		if reported[finding.Line] || !v.inScope(lang, spans, finding.Offset) {
+			continue
 		}
+		violations = append(violations, core.Violation{
+			Type:        finding.Type,
+			Message:     fmt.Sprintf("Обнаружен вероятный секрет в %s (высокая энтропия значения)", finding.Name),
+			Suggestion:  "Используй переменные окружения или секрет-менеджер",
+			Line:        finding.Line,
+			Column:      finding.Column,
+			Fingerprint: secrets.Fingerprint(finding.Value),
+			Severity:    core.LevelCritical,
+		})
 	}
-	return false
+
+	return violations
 }
 
 // generateSuggestions генерирует предложения по исправлению
@@ -230,7 +452,7 @@ func (v *SecretsValidator) generateSuggestions(file *core.FileAnalysis, violatio
 			"Создай test-config.ts для тестовых данных",
 			"Используй TEST_ACCOUNTS константы вместо hardcoded значений",
 		)
-	case ".json", ".yaml", ".yml":
+	case ".json", ".yaml", ".yml", ".toml", ".env":
 		suggestions = append(suggestions,
 			"Используй environment variable substitution",
 			"Создай отдельные конфигурации для test/dev/prod окружений",
@@ -248,33 +470,3 @@ func (v *SecretsValidator) generateSuggestions(file *core.FileAnalysis, violatio
 
 	return suggestions
 }
-
-// IsExceptionFile переопределяет базовый метод с дополнительной логикой
-func (v *SecretsValidator) IsExceptionFile(filePath string) bool {
-	// Базовые исключения
-	if v.BaseValidator.IsExceptionFile(filePath) {
-		return true
-	}
-
-	// Дополнительные исключения для secrets validator
-	secretsExceptions := []string{
-		"/example", "/sample", "/template", "/demo",
-		".example", ".sample", ".template",
-		"/fixtures/", "/mocks/", "/stubs/",
-	}
-
-	for _, exception := range secretsExceptions {
-		if strings.Contains(filePath, exception) {
-			v.logger.Debug("file matched secrets validator exception", "file", filePath, "exception", exception)
-			return true
-		}
-	}
-
-	// Проверяем исключения для тестовых конфигураций
-	if v.isTestConfigException(filePath) {
-		v.logger.Debug("file matched test config exception", "file", filePath)
-		return true
-	}
-
-	return false
-}
@@ -12,20 +12,52 @@ type BaseValidator struct {
 	name       string
 	enabled    bool
 	exceptions []string
+	matcher    *shared.PathMatcher
+	scope      []shared.SpanKind
 	patterns   []*regexp.Regexp
 	logger     core.Logger
 }
 
 // NewBaseValidator создает новый базовый валидатор
+// exceptions - шаблоны исключений в стиле .gitignore, последний совпавший побеждает
 func NewBaseValidator(name string, enabled bool, exceptions []string, logger core.Logger) *BaseValidator {
 	return &BaseValidator{
 		name:       name,
 		enabled:    enabled,
 		exceptions: exceptions,
+		matcher:    shared.NewPathMatcher(exceptions),
 		logger:     logger.With("validator", name),
 	}
 }
 
+// exceptionPatterns собирает шаблоны исключений валидатора по порядку приоритета:
+// встроенные (если не отключены builtin_exemptions: false), специфичные для валидатора
+// extra, затем exception_paths и exception_files из конфигурации
+func exceptionPatterns(name string, config core.ValidatorConfig, extra ...string) []string {
+	var builtin []string
+	if config.UseBuiltinExemptions() {
+		builtin = core.BuiltinExemptions(name)
+	}
+	patterns := make([]string, 0, len(builtin)+len(extra)+len(config.ExceptionPaths)+len(config.ExceptionFiles))
+	patterns = append(patterns, builtin...)
+	patterns = append(patterns, extra...)
+	patterns = append(patterns, config.ExceptionPaths...)
+	return append(patterns, config.ExceptionFiles...)
+}
+
+// setMatchScope задает участки кода для поиска: match_scope из конфигурации
+// или значения валидатора по умолчанию (пустой список - весь текст)
+func (v *BaseValidator) setMatchScope(config core.ValidatorConfig, defaults ...shared.SpanKind) {
+	if len(config.MatchScope) == 0 {
+		v.scope = defaults
+		return
+	}
+	v.scope = nil
+	for _, scope := range config.MatchScope {
+		v.scope = append(v.scope, shared.SpanKind(scope))
+	}
+}
+
 // Name возвращает имя валидатора
 func (v *BaseValidator) Name() string {
 	return v.name
@@ -54,7 +86,7 @@ func (v *BaseValidator) AddPattern(pattern string) error {
 // IsExceptionFile проверяет является ли файл исключением
 // CANONICAL VERSION - использует shared.IsExceptionFile
 func (v *BaseValidator) IsExceptionFile(filePath string) bool {
-	return shared.IsExceptionFile(filePath, v.exceptions, v.logger)
+	return shared.IsExceptionFile(filePath, v.matcher, v.logger)
 }
 
 // FindPatternMatches ищет совпадения с паттернами
@@ -63,6 +95,37 @@ func (v *BaseValidator) FindPatternMatches(content string, patterns []*regexp.Re
 	return shared.FindPatternMatches(content, patterns)
 }
 
+// inScope проверяет, попадает ли смещение в участок из match_scope валидатора
+// Как и в ScopedContent, для неизвестного языка подходит любое смещение
+func (v *BaseValidator) inScope(lang shared.Language, spans shared.Spans, offset int) bool {
+	if len(v.scope) == 0 || lang == shared.LangUnknown {
+		return true
+	}
+	kind := spans.KindAt(offset)
+	for _, scope := range v.scope {
+		if scope == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// ScopedContent возвращает содержимое файла, в котором участки вне match_scope заменены пробелами
+// Номера строк и колонок совпадений остаются как в исходном файле
+func (v *BaseValidator) ScopedContent(file *core.FileAnalysis) string {
+	return shared.ScopeContent(file.Content, shared.LanguageForPath(file.Path), v.scope)
+}
+
+// hasCriticalViolation проверяет есть ли среди нарушений критичные
+func hasCriticalViolation(violations []core.Violation) bool {
+	for _, violation := range violations {
+		if violation.Severity == core.LevelCritical {
+			return true
+		}
+	}
+	return false
+}
+
 // PatternMatch и CreateViolation теперь используются из shared пакета
 // Алиасы для обратной совместимости
 type PatternMatch = shared.PatternMatch